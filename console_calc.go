@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/arconomy/go-math-expression-parser/funcs/numeric"
 	expp "github.com/arconomy/go-math-expression-parser/parser"
 	"github.com/shopspring/decimal"
 )
@@ -32,6 +33,11 @@ func main() {
 	}
 
 	parser := expp.NewParser()
+	// add rounding, min/max and percentage functions
+	if err := numeric.Register(parser.Functions()); err != nil {
+		fmt.Println("Error: ", err)
+		return
+	}
 	// add user function for parsing
 	parser.AddFunction(Foo, "foo")
 
@@ -49,6 +55,9 @@ func main() {
 	exp, err := parser.Parse(formula)
 	if err != nil {
 		fmt.Println("Error: ", err)
+		if perr, ok := err.(*expp.ParseError); ok {
+			fmt.Println(perr.Snippet())
+		}
 		return
 	}
 
@@ -76,7 +85,7 @@ func main() {
 	}
 
 	// execute the expression using values of variables
-	result, err := parser.Evaluate(vars)
+	result, err := exp.Evaluate(vars)
 	if err != nil {
 		fmt.Println("Error: ", err)
 	}
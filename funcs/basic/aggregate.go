@@ -0,0 +1,175 @@
+package basic
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/funcs/decmath"
+	"github.com/shopspring/decimal"
+)
+
+// Aggregate functions accept lists and numbers: the items of a list argument are used instead of the list,
+// so sum(xs), sum(1, 2, 3) and sum(xs, 10) work. The items must be numbers or bools
+
+var (
+	half = decimal.NewFromFloat(0.5)
+
+	// aggregateSignature - lists and numbers are accepted, the result is a number
+	aggregateSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindNumber,
+		Infer: func(name string, args []funcs.Kind) (funcs.Kind, error) {
+			for i, arg := range args {
+				if !arg.AssignableTo(funcs.KindNumber) && arg != funcs.KindList {
+					return funcs.KindNumber, &funcs.TypeError{What: "argument " + strconv.Itoa(i+1) + " of '" + name + "'", Need: funcs.KindList, Get: arg}
+				}
+			}
+			return funcs.KindNumber, nil
+		},
+		NumericItems: true}
+
+	// AggregateFunctions - metadata of the aggregate functions, they are registered by default
+	AggregateFunctions = []funcs.Descriptor{
+		{Name: "sum", Value: SumOf, MinArgs: 1, Variadic: true, Description: "sum(xs, ...) - the sum of the items, 0 for an empty list", Pure: true,
+			Signature: &aggregateSignature},
+		{Name: "avg", Value: Avg, MinArgs: 1, Variadic: true, Description: "avg(xs, ...) - the arithmetic mean of the items", Pure: true,
+			Signature: &aggregateSignature},
+		{Name: "min", Value: MinOf, MinArgs: 1, Variadic: true, Description: "min(xs, ...) - the least item", Pure: true,
+			Signature: &aggregateSignature},
+		{Name: "max", Value: MaxOf, MinArgs: 1, Variadic: true, Description: "max(xs, ...) - the greatest item", Pure: true,
+			Signature: &aggregateSignature},
+		{Name: "count", Value: Count, MinArgs: 1, Variadic: true, Description: "count(xs, ...) - the count of the items of any type", Pure: true,
+			Signature: &funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindNumber}},
+		{Name: "median", Value: Median, MinArgs: 1, Variadic: true, Description: "median(xs, ...) - the middle item, the mean of two middle items for an even count", Pure: true,
+			Signature: &aggregateSignature},
+		{Name: "stddev", Value: StdDev, MinArgs: 1, Variadic: true, Description: "stddev(xs, ...) - the population standard deviation of the items", Pure: true,
+			Signature: &aggregateSignature},
+	}
+)
+
+// SumOf - sum(xs, ...) is the sum of the items
+func SumOf(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	nums, err := aggregateArgs("sum", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	return funcs.NumberValue(sum(nums)), nil
+}
+
+// Avg - avg(xs, ...) is the arithmetic mean of the items rounded by the settings
+func Avg(s funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	nums, err := nonEmptyAggregateArgs("avg", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	res, err := DivWith(s, sum(nums), decimal.NewFromInt(int64(len(nums))))
+	return funcs.NumberValue(res), err
+}
+
+// MinOf - min(xs, ...) is the least item
+func MinOf(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	nums, err := nonEmptyAggregateArgs("min", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	return funcs.NumberValue(decimal.Min(nums[0], nums[1:]...)), nil
+}
+
+// MaxOf - max(xs, ...) is the greatest item
+func MaxOf(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	nums, err := nonEmptyAggregateArgs("max", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	return funcs.NumberValue(decimal.Max(nums[0], nums[1:]...)), nil
+}
+
+// Count - count(xs, ...) is the count of the items, they can have any type
+func Count(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	n := 0
+	for _, arg := range args {
+		if items, err := arg.AsList(); err == nil {
+			n += len(items)
+		} else {
+			n++
+		}
+	}
+	return funcs.NumberValue(decimal.NewFromInt(int64(n))), nil
+}
+
+// Median - median(xs, ...) is the middle item of the sorted items, the mean of two middle items for an even count
+func Median(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	nums, err := nonEmptyAggregateArgs("median", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	sort.Slice(nums, func(i, j int) bool {
+		return nums[i].LessThan(nums[j])
+	})
+	mid := len(nums) / 2
+	if len(nums)%2 == 1 {
+		return funcs.NumberValue(nums[mid]), nil
+	}
+	return funcs.NumberValue(nums[mid-1].Add(nums[mid]).Mul(half)), nil
+}
+
+// StdDev - stddev(xs, ...) is the population standard deviation of the items rounded by the settings
+func StdDev(s funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	nums, err := nonEmptyAggregateArgs("stddev", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	res, err := rounded(s, func(precision int32) (decimal.Decimal, error) {
+		n := decimal.NewFromInt(int64(len(nums)))
+		mean := sum(nums).DivRound(n, precision)
+		squares := decimal.Zero
+		for _, num := range nums {
+			d := num.Sub(mean)
+			squares = squares.Add(d.Mul(d))
+		}
+		return decmath.Sqrt(squares.DivRound(n, precision), precision)
+	})
+	return funcs.NumberValue(res), err
+}
+
+func sum(nums []decimal.Decimal) decimal.Decimal {
+	res := decimal.Zero
+	for _, num := range nums {
+		res = res.Add(num)
+	}
+	return res
+}
+
+// aggregateArgs - the numbers of the arguments of the aggregate function, a list is replaced by its items
+func aggregateArgs(name string, args []funcs.Value) ([]decimal.Decimal, error) {
+	var nums []decimal.Decimal
+	for i, arg := range args {
+		what := "argument " + strconv.Itoa(i+1) + " of '" + name + "'"
+		items, err := arg.AsList()
+		if err != nil {
+			num, err := arg.AsNumber()
+			if err != nil {
+				return nil, funcs.DescribeTypeError(err, what)
+			}
+			nums = append(nums, num)
+			continue
+		}
+		for j, item := range items {
+			num, err := item.AsNumber()
+			if err != nil {
+				return nil, funcs.DescribeTypeError(err, "item "+strconv.Itoa(j+1)+" of "+what)
+			}
+			nums = append(nums, num)
+		}
+	}
+	return nums, nil
+}
+
+// nonEmptyAggregateArgs - the numbers of the arguments, there must be at least one
+func nonEmptyAggregateArgs(name string, args []funcs.Value) ([]decimal.Decimal, error) {
+	nums, err := aggregateArgs(name, args)
+	if err == nil && len(nums) == 0 {
+		return nil, errors.New("'" + name + "' function got no values")
+	}
+	return nums, err
+}
@@ -0,0 +1,88 @@
+package basic_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	dfuncs "github.com/arconomy/go-math-expression-parser/funcs/basic"
+	"github.com/shopspring/decimal"
+)
+
+func TestAggregateFunctions(t *testing.T) {
+	nums := func(strs ...string) funcs.Value {
+		items := make([]funcs.Value, len(strs))
+		for i, s := range strs {
+			items[i] = funcs.NumberValue(decimal.RequireFromString(s))
+		}
+		return funcs.ListValue(items...)
+	}
+	num := func(s string) funcs.Value {
+		return funcs.NumberValue(decimal.RequireFromString(s))
+	}
+	type TestData struct {
+		name string
+		f    funcs.ValueFuncType
+		args []funcs.Value
+		need string
+	}
+
+	data := []TestData{
+		{"SumOf", dfuncs.SumOf, []funcs.Value{nums("1.5", "2", "3")}, "6.5"},
+		{"SumOf", dfuncs.SumOf, []funcs.Value{nums("1", "2"), num("10"), funcs.BoolValue(true)}, "14"},
+		{"SumOf", dfuncs.SumOf, []funcs.Value{nums()}, "0"},
+		{"Avg", dfuncs.Avg, []funcs.Value{nums("1", "2", "4")}, "2.3333333333333333"},
+		{"MinOf", dfuncs.MinOf, []funcs.Value{nums("3", "-1"), num("2")}, "-1"},
+		{"MaxOf", dfuncs.MaxOf, []funcs.Value{num("3"), nums("-1", "7")}, "7"},
+		{"Count", dfuncs.Count, []funcs.Value{funcs.ListValue(funcs.StringValue("a"), funcs.Null), num("1")}, "3"},
+		{"Count", dfuncs.Count, []funcs.Value{nums()}, "0"},
+		{"Median", dfuncs.Median, []funcs.Value{nums("5", "1", "3")}, "3"},
+		{"Median", dfuncs.Median, []funcs.Value{nums("4", "1", "3", "10")}, "3.5"},
+		{"StdDev", dfuncs.StdDev, []funcs.Value{nums("2", "4", "4", "4", "5", "5", "7", "9")}, "2"},
+		{"StdDev", dfuncs.StdDev, []funcs.Value{nums("1", "2")}, "0.5"},
+		{"StdDev", dfuncs.StdDev, []funcs.Value{nums("1", "2", "3")}, "0.816496580927726"},
+	}
+
+	for _, d := range data {
+		res, err := d.f(funcs.DefaultSettings, d.args...)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !res.Equal(num(d.need)) {
+			t.Error("incorrect " + d.name + " result: " + res.String() + ", need: " + d.need)
+		}
+	}
+
+	// the settings round the inexact results
+	res, _ := dfuncs.Avg(funcs.Settings{Precision: 2}, nums("1", "2", "4"))
+	if !res.Equal(num("2.33")) {
+		t.Error("incorrect rounded Avg result: " + res.String())
+	}
+}
+
+func TestAggregateFunctionsErrors(t *testing.T) {
+	type TestData struct {
+		name string
+		f    funcs.ValueFuncType
+		args []funcs.Value
+		err  string
+	}
+
+	data := []TestData{
+		{"SumOf", dfuncs.SumOf, []funcs.Value{funcs.ListValue(funcs.NumberValue(decimal.Zero), funcs.StringValue("x"))},
+			"incorrect type of item 2 of argument 1 of 'sum'. Need: number, but get: string"},
+		{"MaxOf", dfuncs.MaxOf, []funcs.Value{funcs.NumberValue(decimal.Zero), funcs.Null},
+			"incorrect type of argument 2 of 'max'. Need: number, but get: null"},
+		{"Avg", dfuncs.Avg, []funcs.Value{funcs.ListValue()}, "'avg' function got no values"},
+		{"Median", dfuncs.Median, []funcs.Value{funcs.ListValue()}, "'median' function got no values"},
+		{"StdDev", dfuncs.StdDev, []funcs.Value{funcs.ListValue(funcs.ListValue())},
+			"incorrect type of item 1 of argument 1 of 'stddev'. Need: number, but get: list"},
+	}
+
+	for _, d := range data {
+		_, err := d.f(funcs.DefaultSettings, d.args...)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect "+d.name+" error: ", err)
+		}
+	}
+}
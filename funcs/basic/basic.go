@@ -3,96 +3,303 @@ package basic
 import (
 	"errors"
 	"fmt"
-	"math"
-	"strconv"
+	"math/big"
 
 	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/funcs/decmath"
 	"github.com/shopspring/decimal"
 )
 
 var (
-	// the array of operations sorted by operators
+	// DefaultOperators - the precedence table, the array of operations sorted by priority levels
 	// operators[0] - highest operators (unary, functions)
-	// operators[1] - medium operators (*, /, %, ^)
-	// operators[2] - lowest operators (+, -)
+	// operators[1] - exponentiation (^), right-associative, it binds tighter than unary operators
+	// operators[2] - multiplicative operators (*, /, %)
+	// operators[3] - additive operators (+, -)
+	// operators[4] - comparison operators (==, !=, <, <=, >, >=)
+	// operators[5] - logical and (&&)
+	// operators[6] - lowest operators, logical or (||)
 	DefaultOperators = [funcs.LevelsOfPriorities]map[string]funcs.FuncType{
 		{
-			"+":    UnarySum,
-			"-":    UnarySub,
-			"sqrt": Sqrt,
-			"abs":  Abs,
+			"+":     UnarySum,
+			"-":     UnarySub,
+			"!":     Not,
+			"sqrt":  Sqrt,
+			"abs":   Abs,
+			"exp":   Exp,
+			"ln":    Ln,
+			"log10": Log10,
+			"log":   Log,
+		},
+		{
+			"^": Pow, // replaced by PowWith in the registries
 		},
 		{
 			"*": Mult,
-			"/": Div,
+			"/": Div, // replaced by DivWith in the registries
 			"%": DivReminder,
-			"^": Pow,
 		},
 		{
 			"+": Sum,
 			"-": Sub,
 		},
+		{
+			"==": Equal,
+			"!=": NotEqual,
+			"<":  Less,
+			"<=": LessOrEqual,
+			">":  Greater,
+			">=": GreaterOrEqual,
+		},
+		{
+			"&&": And,
+		},
+		{
+			"||": Or,
+		},
+	}
+
+	// DefaultAssociativity - the default binary operators which are not left-associative
+	DefaultAssociativity = map[string]funcs.Associativity{
+		"^": funcs.RightAssoc,
+	}
+
+	// DefaultPostfixOperators - the postfix operators of the default registry
+	DefaultPostfixOperators = []funcs.Operator{
+		{Name: "!", Fixity: funcs.Postfix, Level: funcs.LevelUnary, Func: Factorial},
 	}
+
+	// PercentOperator - '15%' is 0.15. It replaces the binary '%' of a Parser in the percent mode
+	PercentOperator = funcs.Operator{Name: "%", Fixity: funcs.Postfix, Level: funcs.LevelUnary, Func: Percent}
+
+	// RemainderOperator - the default binary '%', '7 % 4' is 3
+	RemainderOperator = funcs.Operator{Name: "%", Fixity: funcs.Infix, Level: funcs.LevelMultiplicative, Func: DivReminder}
+
+	// DefaultFunctions - metadata of the default functions and unary operators
+	DefaultFunctions = []funcs.Descriptor{
+		{Name: "+", Func: UnarySum, MinArgs: 1, MaxArgs: 1, Description: "unary plus, returns x", Pure: true},
+		{Name: "-", Func: UnarySub, MinArgs: 1, MaxArgs: 1, Description: "unary minus, returns -x", Pure: true},
+		{Name: "!", Func: Not, MinArgs: 1, MaxArgs: 1, Description: "logical not, returns 1 if x is 0, otherwise 0", Pure: true},
+		{Name: "if", Lazy: If, MinArgs: 3, MaxArgs: 3, Description: "if(cond, a, b) - a if cond is true, otherwise b. Only one of a and b is evaluated", Pure: true},
+		{Name: "sqrt", WithSettings: SqrtWith, MinArgs: 1, MaxArgs: 1, Description: "sqrt(x) - square root of x", Pure: true},
+		{Name: "abs", Func: Abs, MinArgs: 1, MaxArgs: 1, Description: "abs(x) - absolute value of x", Pure: true},
+		{Name: "exp", WithSettings: ExpWith, MinArgs: 1, MaxArgs: 1, Description: "exp(x) - e to the power of x", Pure: true},
+		{Name: "ln", WithSettings: LnWith, MinArgs: 1, MaxArgs: 1, Description: "ln(x) - natural logarithm of x", Pure: true},
+		{Name: "log10", WithSettings: Log10With, MinArgs: 1, MaxArgs: 1, Description: "log10(x) - decimal logarithm of x", Pure: true},
+		{Name: "log", WithSettings: LogWith, MinArgs: 2, MaxArgs: 2, Description: "log(base, x) - logarithm of x to the base", Pure: true},
+	}
+
+	defaultRegistry = newDefaultRegistry()
 )
 
+func newDefaultRegistry() *funcs.FunctionRegistry {
+	r := funcs.NewFunctionRegistry(DefaultOperators)
+	for _, d := range DefaultFunctions {
+		must(r.RegisterFunction(d))
+	}
+	must(r.RegisterWithSettings(funcs.LevelMultiplicative, "/", DivWith))
+	must(r.RegisterWithSettings(funcs.LevelPower, "^", PowWith))
+	for _, group := range [][]funcs.Descriptor{DefaultValueFunctions, AggregateFunctions, HigherOrderFunctions} {
+		for _, d := range group {
+			must(r.RegisterFunction(d))
+		}
+	}
+	for level, ops := range DefaultValueOperators {
+		for op, f := range ops {
+			must(r.RegisterValue(level, op, f))
+		}
+	}
+	must(r.RegisterLazyValue(funcs.LevelAnd, "&&", ShortCircuitAndValue))
+	must(r.RegisterLazyValue(funcs.LevelOr, "||", ShortCircuitOrValue))
+	for level, sigs := range DefaultValueSignatures {
+		for op, sig := range sigs {
+			r.SetSignature(level, op, sig)
+		}
+	}
+	for op, a := range DefaultAssociativity {
+		r.SetAssociativity(op, a)
+	}
+	for _, op := range DefaultPostfixOperators {
+		must(r.RegisterOperator(op))
+	}
+	return r
+}
+
+// must - the default functions are registered when the package is initialized, so an error is a bug
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// NewRegistry - return a registry with the default operators and functions.
+// It is a copy-on-write clone, so changes of the result don't affect other registries
+func NewRegistry() *funcs.FunctionRegistry {
+	return defaultRegistry.Clone()
+}
+
 func UnarySum(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 1 {
-		return decimal.Zero, errors.New("incorrect count of args for unary sum operator. Need: 2, but get: " + strconv.Itoa(len(args)))
+	if err := funcs.CheckArgsCount("unary sum operator", 1, args); err != nil {
+		return decimal.Zero, err
 	}
 	return args[0], nil
 }
 func UnarySub(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 1 {
-		return decimal.Zero, errors.New("incorrect count of args for unary subtract operator. Need: 2, but get: " + strconv.Itoa(len(args)))
+	if err := funcs.CheckArgsCount("unary subtract operator", 1, args); err != nil {
+		return decimal.Zero, err
 	}
 	return args[0].Neg(), nil
 }
 
+// Sqrt - square root of x with funcs.DefaultSettings
 func Sqrt(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 1 {
-		return decimal.Zero, errors.New("incorrect count of args for 'sqrt' function. Need: 1, but get: " + strconv.Itoa(len(args)))
+	return SqrtWith(funcs.DefaultSettings, args...)
+}
+
+// SqrtWith - square root of x rounded by the settings
+func SqrtWith(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'sqrt' function", 1, args); err != nil {
+		return decimal.Zero, err
 	}
 	if args[0].LessThan(decimal.Zero) {
 		return decimal.Zero, errors.New("'sqrt' function argument is negative: " + fmt.Sprintf("%v", args[0]))
 	}
-	return decimal.NewFromFloat(math.Sqrt(args[0].InexactFloat64())), nil
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Sqrt(args[0], precision)
+	})
+}
+
+// Exp - e to the power of x with funcs.DefaultSettings
+func Exp(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return ExpWith(funcs.DefaultSettings, args...)
+}
+
+// ExpWith - e to the power of x rounded by the settings
+func ExpWith(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'exp' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Exp(args[0], precision)
+	})
+}
+
+// Ln - natural logarithm of x with funcs.DefaultSettings
+func Ln(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return LnWith(funcs.DefaultSettings, args...)
+}
+
+// LnWith - natural logarithm of x rounded by the settings
+func LnWith(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'ln' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Ln(args[0], precision)
+	})
+}
+
+// Log10 - decimal logarithm of x with funcs.DefaultSettings
+func Log10(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return Log10With(funcs.DefaultSettings, args...)
+}
+
+// Log10With - decimal logarithm of x rounded by the settings
+func Log10With(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'log10' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Log10(args[0], precision)
+	})
+}
+
+// Log - log(base, x) is logarithm of x to the base with funcs.DefaultSettings
+func Log(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return LogWith(funcs.DefaultSettings, args...)
+}
+
+// LogWith - logarithm of x to the base rounded by the settings
+func LogWith(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'log' function", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Log(args[0], args[1], precision)
+	})
+}
+
+// rounded - calculate the result with guard digits and round it by the settings,
+// so all rounding modes get correct digits to decide on
+func rounded(s funcs.Settings, f func(precision int32) (decimal.Decimal, error)) (decimal.Decimal, error) {
+	res, err := f(s.Precision + decmath.GuardDigits)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return s.Round(res), nil
 }
 
 func Abs(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 1 {
-		return decimal.Zero, errors.New("incorrect count of args for 'abs' function. Need: 1, but get: " + strconv.Itoa(len(args)))
+	if err := funcs.CheckArgsCount("'abs' function", 1, args); err != nil {
+		return decimal.Zero, err
 	}
 	return args[0].Abs(), nil
 }
 
 func Mult(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 2 {
-		return decimal.Zero, errors.New("incorrect count of args for multiplication operator. Need: 2, but get: " + strconv.Itoa(len(args)))
+	if err := funcs.CheckArgsCount("multiplication operator", 2, args); err != nil {
+		return decimal.Zero, err
 	}
 	return args[0].Mul(args[1]), nil
 }
 
+// Div - division with funcs.DefaultSettings
 func Div(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 2 {
-		return decimal.Zero, errors.New("incorrect count of args for division operator. Need: 2, but get: " + strconv.Itoa(len(args)))
+	return DivWith(funcs.DefaultSettings, args...)
+}
+
+// DivWith - division rounded by the settings
+func DivWith(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("division operator", 2, args); err != nil {
+		return decimal.Zero, err
 	}
 	if args[1].IsZero() {
 		return decimal.Zero, errors.New("incorrect divisor for division operator")
 	}
-
-	return args[0].Div(args[1]), nil
+	return s.Div(args[0], args[1])
 }
 
+// Pow - x to the power of y with funcs.DefaultSettings
 func Pow(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 2 {
-		return decimal.Zero, errors.New("incorrect count of args for power operator. Need: 2, but get: " + strconv.Itoa(len(args)))
+	return PowWith(funcs.DefaultSettings, args...)
+}
+
+// PowWith - x to the power of y. Non-negative integer powers are exact like multiplication,
+// others are rounded by the settings
+func PowWith(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("power operator", 2, args); err != nil {
+		return decimal.Zero, err
 	}
-	return args[0].Pow(args[1]), nil
+	x, y := args[0], args[1]
+	if y.IsInteger() && !x.IsZero() {
+		if !y.IsNegative() {
+			return x.PowBigInt(y.BigInt())
+		}
+		// x^(-n) = 1 / x^n is rounded once
+		p, err := x.PowBigInt(y.Neg().BigInt())
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return s.Div(decimal.NewFromInt(1), p)
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Pow(x, y, precision)
+	})
 }
 
 func DivReminder(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 2 {
-		return decimal.Zero, errors.New("incorrect count of args for % operator. Need: 2, but get: " + strconv.Itoa(len(args)))
+	if err := funcs.CheckArgsCount("% operator", 2, args); err != nil {
+		return decimal.Zero, err
 	}
 	if args[1].IsZero() {
 		return decimal.Zero, errors.New("incorrect divisor for % operator")
@@ -100,16 +307,43 @@ func DivReminder(args ...decimal.Decimal) (decimal.Decimal, error) {
 	return args[0].Mod(args[1]), nil
 }
 
+// maxFactorial - the greatest argument of the factorial, its result has 35660 digits
+const maxFactorial = 10000
+
+// Factorial - n! of a non-negative integer n
+func Factorial(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("factorial operator", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	n := args[0]
+	if !n.IsInteger() || n.IsNegative() {
+		return decimal.Zero, errors.New("factorial of a negative or non-integer number: " + n.String())
+	}
+	if n.GreaterThan(decimal.NewFromInt(maxFactorial)) {
+		return decimal.Zero, errors.New("factorial argument is too large: " + n.String())
+	}
+	res := new(big.Int).MulRange(1, n.IntPart())
+	return decimal.NewFromBigInt(res, 0), nil
+}
+
+// Percent - x% is x / 100, so 200 * 15% is 30 and 50 + 10% is 50.1
+func Percent(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("percent operator", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return args[0].Shift(-2), nil
+}
+
 func Sum(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 2 {
-		return decimal.Zero, errors.New("incorrect count of args for sum operator. Need: 2, but get: " + strconv.Itoa(len(args)))
+	if err := funcs.CheckArgsCount("sum operator", 2, args); err != nil {
+		return decimal.Zero, err
 	}
 	return args[0].Add(args[1]), nil
 }
 
 func Sub(args ...decimal.Decimal) (decimal.Decimal, error) {
-	if len(args) != 2 {
-		return decimal.Zero, errors.New("incorrect count of args for subtract operator. Need: 2, but get: " + strconv.Itoa(len(args)))
+	if err := funcs.CheckArgsCount("subtract operator", 2, args); err != nil {
+		return decimal.Zero, err
 	}
 	return args[0].Sub(args[1]), nil
 }
@@ -3,6 +3,7 @@ package basic_test
 import (
 	"testing"
 
+	"github.com/arconomy/go-math-expression-parser/funcs"
 	dfuncs "github.com/arconomy/go-math-expression-parser/funcs/basic"
 	"github.com/shopspring/decimal"
 )
@@ -306,3 +307,72 @@ func TestDefaultOperators(t *testing.T) {
 		t.Error("incorrect Sub error handling")
 	}
 }
+
+func TestPreciseFunctions(t *testing.T) {
+	type TestData struct {
+		name   string
+		f      funcs.FuncType
+		args   []decimal.Decimal
+		output string
+	}
+
+	data := []TestData{
+		{"Sqrt", dfuncs.Sqrt, []decimal.Decimal{decimal.NewFromInt(2)}, "1.4142135623730950"},
+		{"Exp", dfuncs.Exp, []decimal.Decimal{decimal.NewFromInt(1)}, "2.7182818284590452"},
+		{"Ln", dfuncs.Ln, []decimal.Decimal{decimal.NewFromInt(10)}, "2.3025850929940457"},
+		{"Log10", dfuncs.Log10, []decimal.Decimal{decimal.NewFromInt(100000)}, "5"},
+		{"Log", dfuncs.Log, []decimal.Decimal{decimal.NewFromInt(3), decimal.NewFromInt(81)}, "4"},
+		{"Pow", dfuncs.Pow, []decimal.Decimal{decimal.NewFromInt(2), decimal.NewFromFloat(0.5)}, "1.4142135623730950"},
+	}
+
+	for _, d := range data {
+		res, err := d.f(d.args...)
+		if err != nil {
+			t.Error(err)
+		}
+		if !res.Equal(decimal.RequireFromString(d.output)) {
+			t.Error("incorrect " + d.name + " result: " + res.String())
+		}
+		if _, err := d.f(); err == nil {
+			t.Error("incorrect " + d.name + " error handling")
+		}
+	}
+
+	if _, err := dfuncs.Ln(decimal.Zero); err == nil {
+		t.Error("incorrect Ln error handling")
+	}
+	if _, err := dfuncs.Pow(decimal.NewFromInt(-2), decimal.NewFromFloat(0.5)); err == nil {
+		t.Error("incorrect Pow error handling")
+	}
+}
+
+func TestPostfixOperators(t *testing.T) {
+	res, err := dfuncs.Factorial(decimal.NewFromInt(20))
+	if err != nil || !res.Equal(decimal.RequireFromString("2432902008176640000")) {
+		t.Error("incorrect Factorial result: " + res.String())
+	}
+	res, err = dfuncs.Factorial(decimal.NewFromInt(25))
+	if err != nil || !res.Equal(decimal.RequireFromString("15511210043330985984000000")) {
+		t.Error("incorrect Factorial result: " + res.String())
+	}
+	res, err = dfuncs.Factorial(decimal.Zero)
+	if err != nil || !res.Equal(decimal.NewFromInt(1)) {
+		t.Error("incorrect Factorial result: " + res.String())
+	}
+	for _, n := range []string{"-1", "1.5", "10001"} {
+		if _, err := dfuncs.Factorial(decimal.RequireFromString(n)); err == nil {
+			t.Error("incorrect Factorial error handling for " + n)
+		}
+	}
+	if _, err := dfuncs.Factorial(); err == nil {
+		t.Error("incorrect Factorial error handling")
+	}
+
+	res, err = dfuncs.Percent(decimal.RequireFromString("12.5"))
+	if err != nil || !res.Equal(decimal.RequireFromString("0.125")) {
+		t.Error("incorrect Percent result: " + res.String())
+	}
+	if _, err := dfuncs.Percent(decimal.Zero, decimal.Zero); err == nil {
+		t.Error("incorrect Percent error handling")
+	}
+}
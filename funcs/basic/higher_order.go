@@ -0,0 +1,105 @@
+package basic
+
+import (
+	"strconv"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+)
+
+// Higher-order functions get a list and a function value, like the lambda 'x -> x * 1.2'.
+// They are lazy: the function argument is evaluated by them once into a closure, which is called for every item
+
+var (
+	mapSignature    = funcs.Signature{Params: []funcs.Kind{funcs.KindList, funcs.KindFunc}, Result: funcs.KindList}
+	reduceSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindList, funcs.KindAny, funcs.KindFunc}, Result: funcs.KindAny}
+
+	// HigherOrderFunctions - metadata of the functions over lists and function values, they are registered by default
+	HigherOrderFunctions = []funcs.Descriptor{
+		{Name: "map", LazyValue: Map, MinArgs: 2, MaxArgs: 2, Description: "map(xs, x -> y) - the list of the results of the function for every item", Pure: true,
+			Signature: &mapSignature},
+		{Name: "filter", LazyValue: Filter, MinArgs: 2, MaxArgs: 2, Description: "filter(xs, x -> cond) - the list of the items for which the function is true", Pure: true,
+			Signature: &mapSignature},
+		{Name: "reduce", LazyValue: Reduce, MinArgs: 3, MaxArgs: 3, Description: "reduce(xs, init, (acc, x) -> y) - the function applied to the accumulator and every item, init is the first accumulator", Pure: true,
+			Signature: &reduceSignature},
+	}
+)
+
+// Map - map(xs, f) is the list of f(x) for every item x of xs
+func Map(_ funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+	items, fn, err := listAndFunc("map", args, 1)
+	if err != nil {
+		return funcs.Null, err
+	}
+	res := make([]funcs.Value, len(items))
+	for i, item := range items {
+		if res[i], err = fn.Call(item); err != nil {
+			return funcs.Null, err
+		}
+	}
+	return funcs.ListValue(res...), nil
+}
+
+// Filter - filter(xs, f) is the list of the items x of xs for which f(x) is true
+func Filter(_ funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+	items, fn, err := listAndFunc("filter", args, 1)
+	if err != nil {
+		return funcs.Null, err
+	}
+	res := []funcs.Value{}
+	for _, item := range items {
+		val, err := fn.Call(item)
+		if err != nil {
+			return funcs.Null, err
+		}
+		ok, err := val.AsBool()
+		if err != nil {
+			return funcs.Null, funcs.DescribeTypeError(err, "the result of '"+fn.String()+"'")
+		}
+		if ok {
+			res = append(res, item)
+		}
+	}
+	return funcs.ListValue(res...), nil
+}
+
+// Reduce - reduce(xs, init, f) is f(...f(f(init, x1), x2)..., xn), it is init for an empty list
+func Reduce(_ funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+	items, fn, err := listAndFunc("reduce", args, 2)
+	if err != nil {
+		return funcs.Null, err
+	}
+	acc, err := args[1].Value()
+	if err != nil {
+		return funcs.Null, err
+	}
+	for _, item := range items {
+		if acc, err = fn.Call(acc, item); err != nil {
+			return funcs.Null, err
+		}
+	}
+	return acc, nil
+}
+
+// listAndFunc - the items of the first argument and the function of the argument fnArg
+func listAndFunc(name string, args []funcs.LazyValueArg, fnArg int) ([]funcs.Value, funcs.Callable, error) {
+	if err := funcs.CheckArgsCount(name+" function", fnArg+1, args); err != nil {
+		return nil, nil, err
+	}
+	list, err := args[0].Value()
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := list.AsList()
+	if err != nil {
+		return nil, nil, funcs.DescribeTypeError(err, "argument 1 of '"+name+"'")
+	}
+	val, err := args[fnArg].Value()
+	if err != nil {
+		return nil, nil, err
+	}
+	fn, err := val.AsFunc()
+	if err != nil {
+		return nil, nil, funcs.DescribeTypeError(err, "argument "+strconv.Itoa(fnArg+1)+" of '"+name+"'")
+	}
+	return items, fn, nil
+}
@@ -0,0 +1,122 @@
+package basic_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	dfuncs "github.com/arconomy/go-math-expression-parser/funcs/basic"
+	"github.com/shopspring/decimal"
+)
+
+// testFunc - the function value which calls the Go function and counts the calls
+type testFunc struct {
+	f     func(args ...funcs.Value) (funcs.Value, error)
+	calls int
+}
+
+func (f *testFunc) Call(args ...funcs.Value) (funcs.Value, error) {
+	f.calls++
+	return f.f(args...)
+}
+
+func (f *testFunc) String() string {
+	return "f"
+}
+
+func TestHigherOrderFunctions(t *testing.T) {
+	num := func(n int64) funcs.Value {
+		return funcs.NumberValue(decimal.NewFromInt(n))
+	}
+	nums := func(ns ...int64) funcs.Value {
+		items := make([]funcs.Value, len(ns))
+		for i, n := range ns {
+			items[i] = num(n)
+		}
+		return funcs.ListValue(items...)
+	}
+	double := &testFunc{f: func(args ...funcs.Value) (funcs.Value, error) {
+		x, err := args[0].AsNumber()
+		return funcs.NumberValue(x.Mul(decimal.NewFromInt(2))), err
+	}}
+	positive := &testFunc{f: func(args ...funcs.Value) (funcs.Value, error) {
+		x, err := args[0].AsNumber()
+		return funcs.BoolValue(x.IsPositive()), err
+	}}
+	add := &testFunc{f: func(args ...funcs.Value) (funcs.Value, error) {
+		nums, err := funcs.NumberArgs("add", args)
+		if err != nil {
+			return funcs.Null, err
+		}
+		return funcs.NumberValue(nums[0].Add(nums[1])), nil
+	}}
+
+	type TestData struct {
+		name  string
+		f     funcs.LazyValueFuncType
+		args  []*testValueArg
+		need  funcs.Value
+		fn    *testFunc
+		calls int
+	}
+	data := []TestData{
+		{"Map", dfuncs.Map, []*testValueArg{{val: nums(1, -2, 3)}, {val: funcs.FuncValue(double)}}, nums(2, -4, 6), double, 3},
+		{"Map", dfuncs.Map, []*testValueArg{{val: nums()}, {val: funcs.FuncValue(double)}}, nums(), double, 0},
+		{"Filter", dfuncs.Filter, []*testValueArg{{val: nums(1, -2, 3)}, {val: funcs.FuncValue(positive)}}, nums(1, 3), positive, 3},
+		{"Filter", dfuncs.Filter, []*testValueArg{{val: nums(-1)}, {val: funcs.FuncValue(positive)}}, nums(), positive, 1},
+		{"Reduce", dfuncs.Reduce, []*testValueArg{{val: nums(1, 2, 3)}, {val: num(10)}, {val: funcs.FuncValue(add)}}, num(16), add, 3},
+		{"Reduce", dfuncs.Reduce, []*testValueArg{{val: nums()}, {val: funcs.Null}, {val: funcs.FuncValue(add)}}, funcs.Null, add, 0},
+	}
+
+	for _, d := range data {
+		d.fn.calls = 0
+		args := make([]funcs.LazyValueArg, len(d.args))
+		for i, arg := range d.args {
+			args[i] = arg
+		}
+		res, err := d.f(funcs.DefaultSettings, args...)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !res.Equal(d.need) {
+			t.Error("incorrect " + d.name + " result: " + res.String() + ", need: " + d.need.String())
+		}
+		if d.fn.calls != d.calls {
+			t.Error("incorrect count of calls of " + d.name)
+		}
+		for _, arg := range d.args {
+			if arg.count != 1 {
+				t.Error("argument " + arg.String() + " of " + d.name + " is evaluated more than once")
+			}
+		}
+	}
+}
+
+func TestHigherOrderFunctionsErrors(t *testing.T) {
+	xs := funcs.NumberList(decimal.NewFromInt(1))
+	str := &testFunc{f: func(args ...funcs.Value) (funcs.Value, error) {
+		return funcs.StringValue("a"), nil
+	}}
+	type TestData struct {
+		f    funcs.LazyValueFuncType
+		args []*testValueArg
+		err  string
+	}
+	data := []TestData{
+		{dfuncs.Map, []*testValueArg{{val: funcs.StringValue("a")}, {val: funcs.FuncValue(str)}}, "incorrect type of argument 1 of 'map'. Need: list, but get: string"},
+		{dfuncs.Map, []*testValueArg{{val: xs}, {val: xs}}, "incorrect type of argument 2 of 'map'. Need: function, but get: list"},
+		{dfuncs.Filter, []*testValueArg{{val: xs}, {val: funcs.FuncValue(str)}}, "incorrect type of the result of 'f'. Need: bool, but get: string"},
+		{dfuncs.Reduce, []*testValueArg{{val: xs}, {val: xs}, {val: xs}}, "incorrect type of argument 3 of 'reduce'. Need: function, but get: list"},
+		{dfuncs.Reduce, []*testValueArg{{val: xs}, {val: funcs.FuncValue(str)}}, "incorrect count of args for reduce function. Need: 3, but get: 2"},
+	}
+	for _, d := range data {
+		args := make([]funcs.LazyValueArg, len(d.args))
+		for i, arg := range d.args {
+			args[i] = arg
+		}
+		_, err := d.f(funcs.DefaultSettings, args...)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error: ", err, ", need: "+d.err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package basic
+
+import (
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+// Comparison and logical operators return funcs.True (1) or funcs.False (0),
+// any non-zero argument is treated as true
+
+func Equal(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("== operator", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(args[0].Equal(args[1])), nil
+}
+
+func NotEqual(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("!= operator", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(!args[0].Equal(args[1])), nil
+}
+
+func Less(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("< operator", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(args[0].LessThan(args[1])), nil
+}
+
+func LessOrEqual(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("<= operator", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(args[0].LessThanOrEqual(args[1])), nil
+}
+
+func Greater(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("> operator", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(args[0].GreaterThan(args[1])), nil
+}
+
+func GreaterOrEqual(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount(">= operator", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(args[0].GreaterThanOrEqual(args[1])), nil
+}
+
+func And(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("&& operator", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(funcs.IsTrue(args[0]) && funcs.IsTrue(args[1])), nil
+}
+
+func Or(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("|| operator", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(funcs.IsTrue(args[0]) || funcs.IsTrue(args[1])), nil
+}
+
+func Not(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("! operator", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return funcs.Bool(!funcs.IsTrue(args[0])), nil
+}
+
+// If - if(cond, a, b) returns a if cond is true, otherwise b. Only one of a and b is evaluated
+func If(args ...funcs.LazyArg) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'if' function", 3, args); err != nil {
+		return decimal.Zero, err
+	}
+	cond, err := args[0].Evaluate()
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if funcs.IsTrue(cond) {
+		return args[1].Evaluate()
+	}
+	return args[2].Evaluate()
+}
@@ -0,0 +1,121 @@
+package basic_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	dfuncs "github.com/arconomy/go-math-expression-parser/funcs/basic"
+	"github.com/shopspring/decimal"
+)
+
+func TestLogicOperators(t *testing.T) {
+	one, two := decimal.NewFromInt(1), decimal.NewFromInt(2)
+	type TestData struct {
+		name string
+		f    funcs.FuncType
+		args []decimal.Decimal
+		need decimal.Decimal
+	}
+
+	data := []TestData{
+		{"Equal", dfuncs.Equal, []decimal.Decimal{one, one}, funcs.True},
+		{"Equal", dfuncs.Equal, []decimal.Decimal{decimal.RequireFromString("1.0"), one}, funcs.True},
+		{"Equal", dfuncs.Equal, []decimal.Decimal{one, two}, funcs.False},
+		{"NotEqual", dfuncs.NotEqual, []decimal.Decimal{one, two}, funcs.True},
+		{"Less", dfuncs.Less, []decimal.Decimal{one, two}, funcs.True},
+		{"Less", dfuncs.Less, []decimal.Decimal{two, two}, funcs.False},
+		{"LessOrEqual", dfuncs.LessOrEqual, []decimal.Decimal{two, two}, funcs.True},
+		{"Greater", dfuncs.Greater, []decimal.Decimal{two, one}, funcs.True},
+		{"GreaterOrEqual", dfuncs.GreaterOrEqual, []decimal.Decimal{one, two}, funcs.False},
+		{"And", dfuncs.And, []decimal.Decimal{two, decimal.NewFromFloat(-0.5)}, funcs.True},
+		{"And", dfuncs.And, []decimal.Decimal{one, decimal.Zero}, funcs.False},
+		{"Or", dfuncs.Or, []decimal.Decimal{decimal.Zero, two}, funcs.True},
+		{"Or", dfuncs.Or, []decimal.Decimal{decimal.Zero, decimal.Zero}, funcs.False},
+		{"Not", dfuncs.Not, []decimal.Decimal{decimal.Zero}, funcs.True},
+		{"Not", dfuncs.Not, []decimal.Decimal{two}, funcs.False},
+	}
+
+	for _, d := range data {
+		res, err := d.f(d.args...)
+		if err != nil {
+			t.Error(err)
+		}
+		if !res.Equal(d.need) {
+			t.Error("incorrect " + d.name + " result: " + res.String())
+		}
+
+		res, err = d.f(append(d.args, one)...)
+		if !res.Equal(decimal.Zero) || err == nil {
+			t.Error("incorrect " + d.name + " error handling")
+		}
+	}
+}
+
+// testArg - lazy argument which counts its evaluations
+type testArg struct {
+	val   decimal.Decimal
+	err   error
+	count int
+}
+
+func (a *testArg) Evaluate() (decimal.Decimal, error) {
+	a.count++
+	return a.val, a.err
+}
+
+func (a *testArg) String() string {
+	return a.val.String()
+}
+
+func TestLazyOperators(t *testing.T) {
+	failed := errors.New("must not be evaluated")
+	type TestData struct {
+		name      string
+		f         funcs.LazyFuncType
+		args      []*testArg
+		need      decimal.Decimal
+		evaluated []int
+	}
+
+	data := []TestData{
+		{"If", dfuncs.If, []*testArg{{val: funcs.True}, {val: decimal.NewFromInt(5)}, {err: failed}}, decimal.NewFromInt(5), []int{1, 1, 0}},
+		{"If", dfuncs.If, []*testArg{{val: funcs.False}, {err: failed}, {val: decimal.NewFromInt(7)}}, decimal.NewFromInt(7), []int{1, 0, 1}},
+	}
+
+	for _, d := range data {
+		args := make([]funcs.LazyArg, len(d.args))
+		for i, arg := range d.args {
+			args[i] = arg
+		}
+		res, err := d.f(args...)
+		if err != nil {
+			t.Error(err)
+		}
+		if !res.Equal(d.need) {
+			t.Error("incorrect " + d.name + " result: " + res.String())
+		}
+		for i, arg := range d.args {
+			if arg.count != d.evaluated[i] {
+				t.Error("incorrect count of " + d.name + " argument evaluations")
+			}
+		}
+
+		res, err = d.f(args[1:]...)
+		if !res.Equal(decimal.Zero) || err == nil {
+			t.Error("incorrect " + d.name + " error handling")
+		}
+	}
+
+	// errors of the evaluated arguments
+	_, err := dfuncs.If(&testArg{err: failed}, &testArg{}, &testArg{})
+	if err != failed {
+		t.Error("incorrect If error handling")
+	}
+
+	// call with evaluated arguments
+	res, err := funcs.LazyFuncType(dfuncs.If).Eager()(funcs.False, decimal.NewFromInt(1), decimal.NewFromInt(2))
+	if err != nil || !res.Equal(decimal.NewFromInt(2)) {
+		t.Error("incorrect eager If result: " + res.String())
+	}
+}
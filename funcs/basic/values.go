@@ -0,0 +1,375 @@
+package basic
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/shopspring/decimal"
+)
+
+// Comparison and logical operators over typed values return bools. Numbers and bools are compared as numbers
+// (true is 1), strings are compared with strings only. null is equal to null only,
+// other comparisons with it are type errors
+
+// DefaultValueOperators - the versions of the default operators over typed values, they replace the numeric ones
+// in the registries. The numeric versions are used to evaluate with decimal.Decimal variables
+var DefaultValueOperators = [funcs.LevelsOfPriorities]map[string]funcs.ValueFuncType{
+	funcs.LevelComparison: {
+		"==": EqualValue,
+		"!=": NotEqualValue,
+		"<":  LessValue,
+		"<=": LessOrEqualValue,
+		">":  GreaterValue,
+		">=": GreaterOrEqualValue,
+	},
+}
+
+// DefaultValueSignatures - the types of the default operators over typed values for the type checker
+var DefaultValueSignatures = [funcs.LevelsOfPriorities]map[string]funcs.Signature{
+	funcs.LevelComparison: {
+		"==": equalSignature,
+		"!=": equalSignature,
+		"<":  compareSignature,
+		"<=": compareSignature,
+		">":  compareSignature,
+		">=": compareSignature,
+	},
+	funcs.LevelAnd: {
+		"&&": logicSignature,
+	},
+	funcs.LevelOr: {
+		"||": logicSignature,
+	},
+}
+
+var (
+	logicSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindBool}, Result: funcs.KindBool}
+
+	stringSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindString}, Result: funcs.KindString}
+
+	// the result is a or b
+	ifSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindBool, funcs.KindAny}, Result: funcs.KindAny,
+		Infer: func(_ string, args []funcs.Kind) (funcs.Kind, error) {
+			return funcs.CommonKind(args[1], args[2]), nil
+		}}
+
+	coalesceSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindAny,
+		Infer: func(_ string, args []funcs.Kind) (funcs.Kind, error) {
+			res := funcs.KindNull
+			for _, arg := range args {
+				res = funcs.CommonKind(res, arg)
+			}
+			return res, nil
+		}}
+
+	// the types of == and !=, which are checked like equalValues
+	equalSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindBool,
+		Infer: func(op string, args []funcs.Kind) (funcs.Kind, error) {
+			a, b := args[0], args[1]
+			if a == funcs.KindNull || b == funcs.KindNull {
+				return funcs.KindBool, nil
+			}
+			if a == funcs.KindString || b == funcs.KindString {
+				return funcs.KindBool, kindsError(op, funcs.KindString, args)
+			}
+			return funcs.KindBool, nil
+		}}
+
+	// the types of <, <=, >, >=, which are checked like compareValues
+	compareSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindBool,
+		Infer: func(op string, args []funcs.Kind) (funcs.Kind, error) {
+			if args[0].AssignableTo(funcs.KindString) && args[1].AssignableTo(funcs.KindString) {
+				return funcs.KindBool, nil
+			}
+			return funcs.KindBool, kindsError(op, funcs.KindNumber, args)
+		}}
+)
+
+// kindsError - the *funcs.TypeError of the first argument of op which is not assignable to need
+func kindsError(op string, need funcs.Kind, args []funcs.Kind) error {
+	for i, arg := range args {
+		if !arg.AssignableTo(need) {
+			return &funcs.TypeError{What: "argument " + strconv.Itoa(i+1) + " of '" + op + "'", Need: need, Get: arg}
+		}
+	}
+	return nil
+}
+
+// DefaultValueFunctions - metadata of the default functions and unary operators over typed values,
+// they replace DefaultFunctions with the same names in the registries
+var DefaultValueFunctions = []funcs.Descriptor{
+	{Name: "!", Value: NotValue, MinArgs: 1, MaxArgs: 1, Description: "logical not, returns true if x is false or 0", Pure: true,
+		Signature: &logicSignature},
+	{Name: "if", LazyValue: IfValue, MinArgs: 3, MaxArgs: 3, Description: "if(cond, a, b) - a if cond is true, otherwise b. Only one of a and b is evaluated", Pure: true,
+		Signature: &ifSignature},
+	{Name: "concat", Value: Concat, MinArgs: 1, Variadic: true, Description: "concat(x, ...) - the arguments converted to strings and joined", Pure: true,
+		Signature: &funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindString}},
+	{Name: "len", Value: Len, MinArgs: 1, MaxArgs: 1, Description: "len(s) - count of characters of the string s", Pure: true,
+		Signature: &funcs.Signature{Params: []funcs.Kind{funcs.KindString}, Result: funcs.KindNumber}},
+	{Name: "upper", Value: Upper, MinArgs: 1, MaxArgs: 1, Description: "upper(s) - the string s in upper case", Pure: true,
+		Signature: &stringSignature},
+	{Name: "lower", Value: Lower, MinArgs: 1, MaxArgs: 1, Description: "lower(s) - the string s in lower case", Pure: true,
+		Signature: &stringSignature},
+	{Name: "string", Value: ToString, MinArgs: 1, MaxArgs: 1, Description: "string(x) - x converted to a string, null is \"null\"", Pure: true,
+		Signature: &funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindString}},
+	{Name: "number", Value: ToNumber, MinArgs: 1, MaxArgs: 1, Description: "number(x) - the number from the string x, true is 1 and false is 0", Pure: true,
+		Signature: &funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindNumber}},
+	{Name: "is_null", Value: IsNull, MinArgs: 1, MaxArgs: 1, Description: "is_null(x) - true if x is null", Pure: true,
+		Signature: &funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: funcs.KindBool}},
+	{Name: "coalesce", LazyValue: Coalesce, MinArgs: 1, Variadic: true, Description: "coalesce(x, ...) - the first argument which is not null, the rest are not evaluated", Pure: true,
+		Signature: &coalesceSignature},
+}
+
+func EqualValue(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	eq, err := equalValues("==", args)
+	return funcs.BoolValue(eq), err
+}
+
+func NotEqualValue(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	eq, err := equalValues("!=", args)
+	return funcs.BoolValue(!eq), err
+}
+
+func LessValue(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	cmp, err := compareValues("<", args)
+	return funcs.BoolValue(cmp < 0), err
+}
+
+func LessOrEqualValue(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	cmp, err := compareValues("<=", args)
+	return funcs.BoolValue(cmp <= 0), err
+}
+
+func GreaterValue(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	cmp, err := compareValues(">", args)
+	return funcs.BoolValue(cmp > 0), err
+}
+
+func GreaterOrEqualValue(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	cmp, err := compareValues(">=", args)
+	return funcs.BoolValue(cmp >= 0), err
+}
+
+// equalValues - checks that two values are equal
+func equalValues(op string, args []funcs.Value) (bool, error) {
+	if err := funcs.CheckArgsCount(op+" operator", 2, args); err != nil {
+		return false, err
+	}
+	a, b := args[0], args[1]
+	if a.IsNull() || b.IsNull() {
+		return a.IsNull() && b.IsNull(), nil
+	}
+	if a.Kind() == funcs.KindString || b.Kind() == funcs.KindString {
+		strs, err := stringArgs(op, args)
+		if err != nil {
+			return false, err
+		}
+		return strs[0] == strs[1], nil
+	}
+	nums, err := funcs.NumberArgs(op, args)
+	if err != nil {
+		return false, err
+	}
+	return nums[0].Equal(nums[1]), nil
+}
+
+// compareValues - return -1, 0 or 1 if the first value is less than, equal to or greater than the second one
+func compareValues(op string, args []funcs.Value) (int, error) {
+	if err := funcs.CheckArgsCount(op+" operator", 2, args); err != nil {
+		return 0, err
+	}
+	if args[0].Kind() == funcs.KindString && args[1].Kind() == funcs.KindString {
+		strs, _ := stringArgs(op, args)
+		return strings.Compare(strs[0], strs[1]), nil
+	}
+	nums, err := funcs.NumberArgs(op, args)
+	if err != nil {
+		return 0, err
+	}
+	return nums[0].Cmp(nums[1]), nil
+}
+
+// stringArgs - the strings from the arguments of the function or operator what, other types are a *funcs.TypeError
+func stringArgs(what string, args []funcs.Value) ([]string, error) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		str, err := arg.AsString()
+		if err != nil {
+			return nil, funcs.DescribeTypeError(err, "argument "+strconv.Itoa(i+1)+" of '"+what+"'")
+		}
+		strs[i] = str
+	}
+	return strs, nil
+}
+
+func NotValue(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	if err := funcs.CheckArgsCount("! operator", 1, args); err != nil {
+		return funcs.Null, err
+	}
+	b, err := args[0].AsBool()
+	if err != nil {
+		return funcs.Null, funcs.DescribeTypeError(err, "argument 1 of '!'")
+	}
+	return funcs.BoolValue(!b), nil
+}
+
+// IfValue - if(cond, a, b) returns a if cond is true, otherwise b. a and b can have any type
+func IfValue(_ funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+	if err := funcs.CheckArgsCount("'if' function", 3, args); err != nil {
+		return funcs.Null, err
+	}
+	cond, err := boolArg("if", 0, args[0])
+	if err != nil {
+		return funcs.Null, err
+	}
+	if cond {
+		return args[1].Value()
+	}
+	return args[2].Value()
+}
+
+// ShortCircuitAndValue - && operator over typed values, it doesn't evaluate the right argument if the left one is false
+func ShortCircuitAndValue(_ funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+	return shortCircuitValue("&&", false, args)
+}
+
+// ShortCircuitOrValue - || operator over typed values, it doesn't evaluate the right argument if the left one is true
+func ShortCircuitOrValue(_ funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+	return shortCircuitValue("||", true, args)
+}
+
+// shortCircuitValue - evaluate arguments until one of them equals to stop
+func shortCircuitValue(op string, stop bool, args []funcs.LazyValueArg) (funcs.Value, error) {
+	if err := funcs.CheckArgsCount(op+" operator", 2, args); err != nil {
+		return funcs.Null, err
+	}
+	for i, arg := range args {
+		b, err := boolArg(op, i, arg)
+		if err != nil {
+			return funcs.Null, err
+		}
+		if b == stop {
+			return funcs.BoolValue(stop), nil
+		}
+	}
+	return funcs.BoolValue(!stop), nil
+}
+
+// boolArg - evaluate the argument i of the function or operator what, it must be a bool or a number
+func boolArg(what string, i int, arg funcs.LazyValueArg) (bool, error) {
+	val, err := arg.Value()
+	if err != nil {
+		return false, err
+	}
+	b, err := val.AsBool()
+	return b, funcs.DescribeTypeError(err, "argument "+strconv.Itoa(i+1)+" of '"+what+"'")
+}
+
+// Concat - concat(x, ...) converts the arguments to strings like ToString and joins them
+func Concat(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	var sb strings.Builder
+	for _, arg := range args {
+		sb.WriteString(text(arg))
+	}
+	return funcs.StringValue(sb.String()), nil
+}
+
+// Len - len(s) is the count of characters of the string
+func Len(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	strs, err := stringFuncArgs("len", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	return funcs.NumberValue(decimal.NewFromInt(int64(utf8.RuneCountInString(strs[0])))), nil
+}
+
+// Upper - upper(s) is the string in upper case
+func Upper(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	strs, err := stringFuncArgs("upper", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	return funcs.StringValue(strings.ToUpper(strs[0])), nil
+}
+
+// Lower - lower(s) is the string in lower case
+func Lower(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	strs, err := stringFuncArgs("lower", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	return funcs.StringValue(strings.ToLower(strs[0])), nil
+}
+
+// stringFuncArgs - checks that the function got one string argument
+func stringFuncArgs(name string, args []funcs.Value) ([]string, error) {
+	if err := funcs.CheckArgsCount("'"+name+"' function", 1, args); err != nil {
+		return nil, err
+	}
+	return stringArgs(name, args)
+}
+
+// ToString - string(x) converts x to a string: a number has no exponent, a bool is true or false, null is "null"
+func ToString(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	if err := funcs.CheckArgsCount("'string' function", 1, args); err != nil {
+		return funcs.Null, err
+	}
+	return funcs.StringValue(text(args[0])), nil
+}
+
+// text - the value as a string without quotes
+func text(v funcs.Value) string {
+	if str, err := v.AsString(); err == nil {
+		return str
+	}
+	return v.String()
+}
+
+// ToNumber - number(x) converts the string with a decimal number to the number, a number is returned as is
+// and a bool is 1 or 0
+func ToNumber(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	if err := funcs.CheckArgsCount("'number' function", 1, args); err != nil {
+		return funcs.Null, err
+	}
+	str, err := args[0].AsString()
+	if err != nil {
+		nums, err := funcs.NumberArgs("number", args)
+		if err != nil {
+			return funcs.Null, err
+		}
+		return funcs.NumberValue(nums[0]), nil
+	}
+	// the grammar of numeric literals, so number("0xFF") is the same as 0xFF
+	num, ok := internal.ParseNumber(strings.TrimSpace(str))
+	if !ok {
+		return funcs.Null, errors.New("'number' function argument is not a number: " + args[0].String())
+	}
+	return funcs.NumberValue(num), nil
+}
+
+// IsNull - is_null(x) is true if x is null
+func IsNull(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	if err := funcs.CheckArgsCount("'is_null' function", 1, args); err != nil {
+		return funcs.Null, err
+	}
+	return funcs.BoolValue(args[0].IsNull()), nil
+}
+
+// Coalesce - coalesce(x, ...) returns the first argument which is not null, the arguments after it are not evaluated.
+// It is null if all arguments are null
+func Coalesce(_ funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+	for _, arg := range args {
+		val, err := arg.Value()
+		if err != nil {
+			return funcs.Null, err
+		}
+		if !val.IsNull() {
+			return val, nil
+		}
+	}
+	return funcs.Null, nil
+}
@@ -0,0 +1,172 @@
+package basic_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	dfuncs "github.com/arconomy/go-math-expression-parser/funcs/basic"
+	"github.com/shopspring/decimal"
+)
+
+func TestValueFunctions(t *testing.T) {
+	num := func(s string) funcs.Value {
+		return funcs.NumberValue(decimal.RequireFromString(s))
+	}
+	str, yes, no := funcs.StringValue, funcs.BoolValue(true), funcs.BoolValue(false)
+	type TestData struct {
+		name string
+		f    funcs.ValueFuncType
+		args []funcs.Value
+		need funcs.Value
+	}
+
+	data := []TestData{
+		{"EqualValue", dfuncs.EqualValue, []funcs.Value{num("1.0"), num("1")}, yes},
+		{"EqualValue", dfuncs.EqualValue, []funcs.Value{yes, num("1")}, yes},
+		{"EqualValue", dfuncs.EqualValue, []funcs.Value{str("USD"), str("USD")}, yes},
+		{"EqualValue", dfuncs.EqualValue, []funcs.Value{funcs.Null, funcs.Null}, yes},
+		{"EqualValue", dfuncs.EqualValue, []funcs.Value{funcs.Null, num("0")}, no},
+		{"NotEqualValue", dfuncs.NotEqualValue, []funcs.Value{str("USD"), str("EUR")}, yes},
+		{"LessValue", dfuncs.LessValue, []funcs.Value{str("EUR"), str("USD")}, yes},
+		{"LessValue", dfuncs.LessValue, []funcs.Value{num("2"), num("10")}, yes},
+		{"LessOrEqualValue", dfuncs.LessOrEqualValue, []funcs.Value{num("2"), num("2")}, yes},
+		{"GreaterValue", dfuncs.GreaterValue, []funcs.Value{yes, no}, yes},
+		{"GreaterOrEqualValue", dfuncs.GreaterOrEqualValue, []funcs.Value{str("a"), str("b")}, no},
+		{"NotValue", dfuncs.NotValue, []funcs.Value{num("0")}, yes},
+		{"NotValue", dfuncs.NotValue, []funcs.Value{yes}, no},
+		{"Concat", dfuncs.Concat, []funcs.Value{str("total: "), num("1.50"), str(" "), yes, str(" "), funcs.Null}, str("total: 1.5 true null")},
+		{"Len", dfuncs.Len, []funcs.Value{str("доход")}, num("5")},
+		{"Upper", dfuncs.Upper, []funcs.Value{str("usd")}, str("USD")},
+		{"Lower", dfuncs.Lower, []funcs.Value{str("USD")}, str("usd")},
+		{"ToString", dfuncs.ToString, []funcs.Value{num("1e3")}, str("1000")},
+		{"ToString", dfuncs.ToString, []funcs.Value{str("a")}, str("a")},
+		{"ToNumber", dfuncs.ToNumber, []funcs.Value{str(" 12.5 ")}, num("12.5")},
+		{"ToNumber", dfuncs.ToNumber, []funcs.Value{str("0xFF")}, num("255")},
+		{"ToNumber", dfuncs.ToNumber, []funcs.Value{str("-1_000")}, num("-1000")},
+		{"ToNumber", dfuncs.ToNumber, []funcs.Value{str("1.5e-3")}, num("0.0015")},
+		{"ToNumber", dfuncs.ToNumber, []funcs.Value{no}, num("0")},
+		{"IsNull", dfuncs.IsNull, []funcs.Value{funcs.Null}, yes},
+		{"IsNull", dfuncs.IsNull, []funcs.Value{str("")}, no},
+	}
+
+	for _, d := range data {
+		res, err := d.f(funcs.DefaultSettings, d.args...)
+		if err != nil {
+			t.Error(err)
+		}
+		if !res.Equal(d.need) {
+			t.Error("incorrect " + d.name + " result: " + res.String() + ", need: " + d.need.String())
+		}
+	}
+}
+
+func TestValueFunctionsErrors(t *testing.T) {
+	num := funcs.NumberValue(decimal.NewFromInt(1))
+	type TestData struct {
+		name string
+		f    funcs.ValueFuncType
+		args []funcs.Value
+		err  string
+	}
+
+	data := []TestData{
+		{"EqualValue", dfuncs.EqualValue, []funcs.Value{funcs.StringValue("1"), num},
+			"incorrect type of argument 2 of '=='. Need: string, but get: number"},
+		{"LessValue", dfuncs.LessValue, []funcs.Value{num, funcs.Null},
+			"incorrect type of argument 2 of '<'. Need: number, but get: null"},
+		{"LessValue", dfuncs.LessValue, []funcs.Value{num},
+			"incorrect count of args for < operator. Need: 2, but get: 1"},
+		{"NotValue", dfuncs.NotValue, []funcs.Value{funcs.StringValue("")},
+			"incorrect type of argument 1 of '!'. Need: bool, but get: string"},
+		{"Len", dfuncs.Len, []funcs.Value{num},
+			"incorrect type of argument 1 of 'len'. Need: string, but get: number"},
+		{"ToNumber", dfuncs.ToNumber, []funcs.Value{funcs.StringValue("abc")},
+			"'number' function argument is not a number: \"abc\""},
+		{"ToNumber", dfuncs.ToNumber, []funcs.Value{funcs.Null},
+			"incorrect type of argument 1 of 'number'. Need: number, but get: null"},
+	}
+
+	for _, d := range data {
+		_, err := d.f(funcs.DefaultSettings, d.args...)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect "+d.name+" error: ", err)
+		}
+	}
+}
+
+// testValueArg - lazy argument over values which counts its evaluations
+type testValueArg struct {
+	val   funcs.Value
+	err   error
+	count int
+}
+
+func (a *testValueArg) Value() (funcs.Value, error) {
+	a.count++
+	return a.val, a.err
+}
+
+func (a *testValueArg) Evaluate() (decimal.Decimal, error) {
+	val, err := a.Value()
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return val.AsNumber()
+}
+
+func (a *testValueArg) String() string {
+	return a.val.String()
+}
+
+func TestLazyValueFunctions(t *testing.T) {
+	failed := errors.New("must not be evaluated")
+	usd := funcs.StringValue("USD")
+	type TestData struct {
+		name      string
+		f         funcs.LazyValueFuncType
+		args      []*testValueArg
+		need      funcs.Value
+		evaluated []int
+	}
+
+	data := []TestData{
+		{"IfValue", dfuncs.IfValue, []*testValueArg{{val: funcs.BoolValue(true)}, {val: usd}, {err: failed}}, usd, []int{1, 1, 0}},
+		{"IfValue", dfuncs.IfValue, []*testValueArg{{val: funcs.NumberValue(decimal.Zero)}, {err: failed}, {val: funcs.Null}}, funcs.Null, []int{1, 0, 1}},
+		{"ShortCircuitAndValue", dfuncs.ShortCircuitAndValue, []*testValueArg{{val: funcs.BoolValue(false)}, {err: failed}}, funcs.BoolValue(false), []int{1, 0}},
+		{"ShortCircuitAndValue", dfuncs.ShortCircuitAndValue, []*testValueArg{{val: funcs.BoolValue(true)}, {val: funcs.NumberValue(decimal.NewFromInt(3))}}, funcs.BoolValue(true), []int{1, 1}},
+		{"ShortCircuitOrValue", dfuncs.ShortCircuitOrValue, []*testValueArg{{val: funcs.NumberValue(decimal.NewFromInt(-1))}, {err: failed}}, funcs.BoolValue(true), []int{1, 0}},
+		{"ShortCircuitOrValue", dfuncs.ShortCircuitOrValue, []*testValueArg{{val: funcs.BoolValue(false)}, {val: funcs.NumberValue(funcs.True)}}, funcs.BoolValue(true), []int{1, 1}},
+		{"ShortCircuitOrValue", dfuncs.ShortCircuitOrValue, []*testValueArg{{val: funcs.BoolValue(false)}, {val: funcs.NumberValue(decimal.Zero)}}, funcs.BoolValue(false), []int{1, 1}},
+		{"Coalesce", dfuncs.Coalesce, []*testValueArg{{val: funcs.Null}, {val: usd}, {err: failed}}, usd, []int{1, 1, 0}},
+		{"Coalesce", dfuncs.Coalesce, []*testValueArg{{val: funcs.Null}}, funcs.Null, []int{1}},
+	}
+
+	for _, d := range data {
+		args := make([]funcs.LazyValueArg, len(d.args))
+		for i, arg := range d.args {
+			args[i] = arg
+		}
+		res, err := d.f(funcs.DefaultSettings, args...)
+		if err != nil {
+			t.Error(err)
+		}
+		if !res.Equal(d.need) {
+			t.Error("incorrect " + d.name + " result: " + res.String())
+		}
+		for i, arg := range d.args {
+			if arg.count != d.evaluated[i] {
+				t.Error("incorrect count of " + d.name + " argument evaluations")
+			}
+		}
+	}
+
+	_, err := dfuncs.IfValue(funcs.DefaultSettings, &testValueArg{val: usd}, &testValueArg{}, &testValueArg{})
+	if err == nil || err.Error() != "incorrect type of argument 1 of 'if'. Need: bool, but get: string" {
+		t.Error("incorrect IfValue error: ", err)
+	}
+	_, err = dfuncs.ShortCircuitAndValue(funcs.DefaultSettings, &testValueArg{val: funcs.BoolValue(true)}, &testValueArg{err: failed})
+	if err != failed {
+		t.Error("incorrect ShortCircuitAndValue error handling")
+	}
+}
@@ -1,9 +1,135 @@
 package funcs
 
-import "github.com/shopspring/decimal"
+import (
+	"errors"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
 
 // FuncType - internal type of functions
 type FuncType func(args ...decimal.Decimal) (decimal.Decimal, error)
 
-// count of operator priorities
-const LevelsOfPriorities = 3
+// priority levels of operators, a lower level binds tighter
+const (
+	LevelUnary          = iota // unary operators and functions
+	LevelPower                 // '^', it binds tighter than unary operators: '-2^2' is '-(2^2)'
+	LevelMultiplicative        // '*', '/', '%'
+	LevelAdditive              // '+', '-'
+	LevelComparison            // '==', '!=', '<', '<=', '>', '>='
+	LevelAnd                   // '&&'
+	LevelOr                    // '||'
+
+	// count of operator priorities
+	LevelsOfPriorities
+)
+
+// Associativity - the grouping of binary operators of the same priority level
+type Associativity int
+
+const (
+	LeftAssoc  Associativity = iota // 'a - b - c' is '(a - b) - c'
+	RightAssoc                      // 'a ^ b ^ c' is 'a ^ (b ^ c)'
+)
+
+var (
+	// True - the result of a true condition
+	True = decimal.NewFromInt(1)
+	// False - the result of a false condition
+	False = decimal.Zero
+)
+
+// Bool - convert the condition to True or False
+func Bool(b bool) decimal.Decimal {
+	if b {
+		return True
+	}
+	return False
+}
+
+// IsTrue - any non-zero value is true
+func IsTrue(d decimal.Decimal) bool {
+	return !d.IsZero()
+}
+
+// Descriptor - the function with its metadata, which is used to check calls at parsing time
+// and to list available functions
+type Descriptor struct {
+	Name         string
+	Func         FuncType
+	Lazy         LazyFuncType      // set instead of Func for functions with lazy arguments
+	WithSettings SettingsFuncType  // set instead of Func for functions which depend on the arithmetic settings
+	Value        ValueFuncType     // set instead of Func for functions over typed values
+	LazyValue    LazyValueFuncType // set instead of Func for functions over typed values with lazy arguments
+	Signature    *Signature        // optional types of the arguments and the result for the type checker
+	MinArgs      int
+	MaxArgs      int  // ignored if the function is variadic
+	Variadic     bool // accepts MinArgs or more arguments
+	Description  string
+	Pure         bool // the result depends on the arguments only
+}
+
+// Validate - checks that the descriptor can be registered
+func (d Descriptor) Validate() error {
+	if d.Name == "" {
+		return errors.New("function name is empty")
+	}
+	set := 0
+	for _, isSet := range []bool{d.Func != nil, d.Lazy != nil, d.WithSettings != nil, d.Value != nil, d.LazyValue != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("function '" + d.Name + "' must have exactly one of Func, Lazy, WithSettings, Value and LazyValue")
+	}
+	if d.MinArgs < 0 || !d.Variadic && d.MaxArgs < d.MinArgs {
+		return errors.New("incorrect count of args for '" + d.Name + "' function: " +
+			strconv.Itoa(d.MinArgs) + ".." + strconv.Itoa(d.MaxArgs))
+	}
+	return nil
+}
+
+// Eager - the function which accepts evaluated arguments, a function which depends
+// on the settings uses DefaultSettings
+func (d Descriptor) Eager() FuncType {
+	switch {
+	case d.Lazy != nil:
+		return d.Lazy.Eager()
+	case d.WithSettings != nil:
+		return d.WithSettings.Bind(DefaultSettings)
+	case d.Value != nil:
+		return d.Value.Numeric(DefaultSettings)
+	case d.LazyValue != nil:
+		return d.LazyValue.Numeric(DefaultSettings).Eager()
+	}
+	return d.Func
+}
+
+// CheckArgs - checks that the function accepts n arguments
+func (d Descriptor) CheckArgs(n int) error {
+	if n >= d.MinArgs && (d.Variadic || n <= d.MaxArgs) {
+		return nil
+	}
+	return errors.New("incorrect count of args for '" + d.Name + "' function. Need: " + d.Arity() + ", but get: " + strconv.Itoa(n))
+}
+
+// Arity - human-readable count of arguments, like "1", "2..3" or "1 or more"
+func (d Descriptor) Arity() string {
+	switch {
+	case d.Variadic:
+		return strconv.Itoa(d.MinArgs) + " or more"
+	case d.MinArgs == d.MaxArgs:
+		return strconv.Itoa(d.MinArgs)
+	}
+	return strconv.Itoa(d.MinArgs) + ".." + strconv.Itoa(d.MaxArgs)
+}
+
+// CheckArgsCount - the common check of arguments for functions with fixed arity,
+// 'what' is the function or operator name used in the error message
+func CheckArgsCount[T any](what string, need int, args []T) error {
+	if len(args) != need {
+		return errors.New("incorrect count of args for " + what + ". Need: " + strconv.Itoa(need) + ", but get: " + strconv.Itoa(len(args)))
+	}
+	return nil
+}
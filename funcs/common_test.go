@@ -0,0 +1,58 @@
+package funcs_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+func TestDescriptor(t *testing.T) {
+	type TestData struct {
+		d     funcs.Descriptor
+		arity string
+		ok    []int
+		fail  []int
+	}
+
+	data := []TestData{
+		{funcs.Descriptor{Name: "f", Func: one, MinArgs: 1, MaxArgs: 1}, "1", []int{1}, []int{0, 2}},
+		{funcs.Descriptor{Name: "f", Func: one, MinArgs: 2, MaxArgs: 3}, "2..3", []int{2, 3}, []int{1, 4}},
+		{funcs.Descriptor{Name: "f", Func: one, MinArgs: 1, Variadic: true}, "1 or more", []int{1, 10}, []int{0}},
+		{funcs.Descriptor{Name: "f", Func: one, Variadic: true}, "0 or more", []int{0, 1}, nil},
+	}
+
+	for _, d := range data {
+		if err := d.d.Validate(); err != nil {
+			t.Error(err)
+		}
+		if d.d.Arity() != d.arity {
+			t.Error("incorrect arity: " + d.d.Arity())
+		}
+		for _, n := range d.ok {
+			if err := d.d.CheckArgs(n); err != nil {
+				t.Error(err)
+			}
+		}
+		for _, n := range d.fail {
+			if err := d.d.CheckArgs(n); err == nil {
+				t.Error("incorrect count of args was not handled")
+			}
+		}
+	}
+
+	for _, d := range []funcs.Descriptor{
+		{Func: one},
+		{Name: "f"},
+		{Name: "f", Func: one, MinArgs: 2, MaxArgs: 1},
+		{Name: "f", Func: one, MinArgs: -1, Variadic: true},
+	} {
+		if err := d.Validate(); err == nil {
+			t.Error("incorrect descriptor was not handled")
+		}
+	}
+
+	if err := funcs.CheckArgsCount("f", 2, []decimal.Decimal{}); err == nil || err.Error() != "incorrect count of args for f. Need: 2, but get: 0" {
+		t.Error("incorrect error: ", err)
+	}
+}
@@ -0,0 +1,298 @@
+// Package decmath - elementary functions of decimal.Decimal computed to arbitrary precision.
+// The precision argument is the count of digits after the decimal point of the result,
+// the result is rounded half away from zero to this count of digits
+package decmath
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPrecision - precision which is used by the default functions,
+// it is equal to the default decimal.DivisionPrecision
+const DefaultPrecision = 16
+
+// GuardDigits - additional digits of intermediate results, which compensate rounding errors.
+// A caller which rounds the result by its own rule requests this count of extra digits
+const GuardDigits = 10
+
+// maxExpArgument - exp(x) for greater x has too many digits to be calculated
+const maxExpArgument = 100000
+
+// maxIterations - limit of Newton iterations, the convergence is quadratic, so it is never reached
+const maxIterations = 1000
+
+var (
+	one  = decimal.NewFromInt(1)
+	two  = decimal.NewFromInt(2)
+	half = decimal.New(5, -1)
+)
+
+// Sqrt - square root of x calculated by Newton's method
+func Sqrt(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	if x.IsNegative() {
+		return decimal.Zero, errors.New("square root of negative number: " + x.String())
+	}
+	if x.IsZero() {
+		return decimal.Zero, nil
+	}
+
+	// x = m * 10^(2k), where m is in [0.01, 100), so sqrt(x) = sqrt(m) * 10^k
+	k := order(x) / 2
+	m := x.Shift(-2 * k)
+	places := max32(precision+k, 0) + GuardDigits
+
+	y := decimal.NewFromFloat(math.Sqrt(m.InexactFloat64()))
+	eps := decimal.New(1, -places)
+	for i := 0; i < maxIterations; i++ {
+		next := y.Add(m.DivRound(y, places)).Mul(half).Round(places)
+		done := next.Sub(y).Abs().LessThanOrEqual(eps)
+		y = next
+		if done {
+			break
+		}
+	}
+	return y.Shift(k).Round(precision), nil
+}
+
+// Exp - e to the power of x
+func Exp(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	if x.IsZero() {
+		return one, nil
+	}
+	if x.Abs().GreaterThan(decimal.NewFromInt(maxExpArgument)) {
+		if x.IsNegative() {
+			return decimal.Zero, nil
+		}
+		return decimal.Zero, errors.New("too large argument of exponent: " + x.String())
+	}
+	if x.IsNegative() {
+		// exp(-x) = 1 / exp(x), the relative error of exp(x) is kept by the division
+		y := expPositive(x.Neg(), precision+GuardDigits)
+		return one.DivRound(y, precision), nil
+	}
+	return expPositive(x, precision).Round(precision), nil
+}
+
+// expPositive - exp(x) for x > 0 with at least 'places' correct digits after the decimal point
+func expPositive(x decimal.Decimal, places int32) decimal.Decimal {
+	// exp(x) = exp(x / 2^k)^(2^k), where x / 2^k < 0.5
+	k := int32(0)
+	r := x
+	for r.GreaterThan(half) {
+		r = r.Mul(half)
+		k++
+	}
+
+	// count of significant digits: digits of the integer part of exp(x),
+	// requested places and digits lost while squaring
+	intDigits := int32(x.InexactFloat64()/math.Ln10) + 1
+	significant := intDigits + places + GuardDigits + k/3 + 1
+
+	// Taylor series of exp(r), exp(r) is in [1, 1.65), so significant digits are places
+	sum, term := one, one
+	eps := decimal.New(1, -significant)
+	for n := int64(1); ; n++ {
+		term = term.Mul(r).DivRound(decimal.NewFromInt(n), significant)
+		if term.IsZero() || term.LessThan(eps) {
+			break
+		}
+		sum = sum.Add(term)
+	}
+
+	for i := int32(0); i < k; i++ {
+		sum = roundSignificant(sum.Mul(sum), significant)
+	}
+	return sum
+}
+
+// Ln - natural logarithm of x
+func Ln(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	if !x.IsPositive() {
+		return decimal.Zero, errors.New("logarithm of non-positive number: " + x.String())
+	}
+	return ln(x, precision+GuardDigits).Round(precision), nil
+}
+
+// ln - natural logarithm of x > 0 with at least 'places' correct digits after the decimal point
+func ln(x decimal.Decimal, places int32) decimal.Decimal {
+	if x.Equal(one) {
+		return decimal.Zero
+	}
+
+	// x = m * 10^e, where m is in [0.1, 1)
+	e := order(x)
+	m := x.Shift(-e)
+	// the error of ln(10) is multiplied by e
+	places += int32(len(big.NewInt(int64(e)).String()))
+
+	// m * 2^c is in [0.75, 1.5)
+	c := int32(0)
+	for m.LessThan(decimal.New(75, -2)) {
+		m = m.Mul(two)
+		c++
+	}
+
+	// ln(x) = ln(m * 2^c) - c * ln(2) + e * ln(10)
+	res := lnNearOne(m, places)
+	if c != 0 || e != 0 {
+		ln2 := ln2(places)
+		res = res.Sub(ln2.Mul(decimal.NewFromInt32(c)))
+		if e != 0 {
+			res = res.Add(ln10(ln2, places).Mul(decimal.NewFromInt32(e)))
+		}
+	}
+	return res.Round(places)
+}
+
+// lnNearOne - ln(y) = 2 * atanh((y - 1) / (y + 1)), it converges quickly if y is near 1
+func lnNearOne(y decimal.Decimal, places int32) decimal.Decimal {
+	z := y.Sub(one).DivRound(y.Add(one), places+2)
+	return atanh(z, places).Mul(two)
+}
+
+// atanh - the series z + z^3/3 + z^5/5 + ..., |z| < 1
+func atanh(z decimal.Decimal, places int32) decimal.Decimal {
+	places += 2
+	z2 := z.Mul(z).Round(places)
+	sum, pow := z, z
+	eps := decimal.New(1, -places)
+	for n := int64(3); ; n += 2 {
+		pow = pow.Mul(z2).Round(places)
+		term := pow.DivRound(decimal.NewFromInt(n), places)
+		if term.Abs().LessThan(eps) {
+			break
+		}
+		sum = sum.Add(term)
+	}
+	return sum
+}
+
+// ln2 - ln(2) = 2 * atanh(1/3)
+func ln2(places int32) decimal.Decimal {
+	return atanh(one.DivRound(decimal.NewFromInt(3), places+2), places).Mul(two)
+}
+
+// ln10 - ln(10) = 3 * ln(2) + ln(1.25) = 3 * ln(2) + 2 * atanh(1/9)
+func ln10(ln2 decimal.Decimal, places int32) decimal.Decimal {
+	ln125 := atanh(one.DivRound(decimal.NewFromInt(9), places+2), places).Mul(two)
+	return ln2.Mul(decimal.NewFromInt(3)).Add(ln125)
+}
+
+// Log10 - decimal logarithm of x, it is exact for powers of 10
+func Log10(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	if !x.IsPositive() {
+		return decimal.Zero, errors.New("logarithm of non-positive number: " + x.String())
+	}
+	if isPowerOfTen(x) {
+		return decimal.NewFromInt32(order(x) - 1), nil
+	}
+	places := precision + GuardDigits
+	return ln(x, places).DivRound(ln10(ln2(places), places), places).Round(precision), nil
+}
+
+// Log - logarithm of x to the base
+func Log(base, x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	if !base.IsPositive() || base.Equal(one) {
+		return decimal.Zero, errors.New("incorrect base of logarithm: " + base.String())
+	}
+	if !x.IsPositive() {
+		return decimal.Zero, errors.New("logarithm of non-positive number: " + x.String())
+	}
+	places := precision + GuardDigits
+	// the division by a small ln(base) increases the error
+	if lnBase := math.Abs(math.Log(base.InexactFloat64())); lnBase < 1 {
+		places += int32(-math.Log10(lnBase)) + 1
+	}
+	return ln(x, places).DivRound(ln(base, places), places).Round(precision), nil
+}
+
+// Pow - x to the power of y. Non-negative integer powers are exact,
+// others are rounded to precision digits. 0^0 is 1 like in decimal.Pow
+func Pow(x, y decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	if x.IsZero() {
+		switch {
+		case y.IsNegative():
+			return decimal.Zero, errors.New("zero to negative power: " + y.String())
+		case y.IsZero():
+			return one, nil
+		}
+		return decimal.Zero, nil
+	}
+	if y.IsInteger() {
+		if !y.IsNegative() {
+			return x.PowBigInt(y.BigInt())
+		}
+		// x^(-n) = 1 / x^n
+		p, err := x.PowBigInt(y.Neg().BigInt())
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return one.DivRound(p, precision), nil
+	}
+	if x.IsNegative() {
+		return decimal.Zero, errors.New("negative number to non-integer power: " + x.String() + "^" + y.String())
+	}
+	if y.Mul(two).IsInteger() {
+		// x^(n + 0.5) = x^n * sqrt(x), it is exact if the root is
+		n := y.Sub(half).BigInt()
+		return powHalf(x, decimal.NewFromBigInt(n, 0), precision)
+	}
+
+	// x^y = exp(y * ln(x)), the error of ln(x) is multiplied by y and by digits of the result
+	resDigits := y.InexactFloat64() * math.Log10(x.InexactFloat64())
+	places := precision + GuardDigits + int32(len(y.Truncate(0).String()))
+	if resDigits > 0 {
+		places += int32(math.Min(resDigits, math.MaxInt32/2))
+	}
+	return Exp(y.Mul(ln(x, places)), precision)
+}
+
+// powHalf - x^(n + 0.5) = x^n * sqrt(x)
+func powHalf(x, n decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	p, err := Pow(x, n, precision+GuardDigits)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	// the error of the root is multiplied by x^n
+	extra := max32(order(p), 0)
+	root, err := Sqrt(x, precision+GuardDigits+extra)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return p.Mul(root).Round(precision), nil
+}
+
+// order - count of digits of the integer part, so x = m * 10^order(x), where m is in [0.1, 1)
+func order(x decimal.Decimal) int32 {
+	return int32(x.NumDigits()) + x.Exponent()
+}
+
+// roundSignificant - round x to n significant digits
+func roundSignificant(x decimal.Decimal, n int32) decimal.Decimal {
+	return x.Round(n - order(x))
+}
+
+// isPowerOfTen - checks that x is 10^n, n may be negative
+func isPowerOfTen(x decimal.Decimal) bool {
+	c := x.Coefficient()
+	ten := big.NewInt(10)
+	r := new(big.Int)
+	for c.Cmp(ten) >= 0 {
+		c.QuoRem(c, ten, r)
+		if r.Sign() != 0 {
+			return false
+		}
+	}
+	return c.Cmp(big.NewInt(1)) == 0
+}
+
+func max32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
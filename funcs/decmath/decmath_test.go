@@ -0,0 +1,146 @@
+package decmath_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs/decmath"
+	"github.com/shopspring/decimal"
+)
+
+type TestData struct {
+	args   []string
+	output string
+}
+
+func check(t *testing.T, name string, f func(args ...decimal.Decimal) (decimal.Decimal, error), data []TestData) {
+	for _, d := range data {
+		var args []decimal.Decimal
+		for _, a := range d.args {
+			args = append(args, decimal.RequireFromString(a))
+		}
+		res, err := f(args...)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !res.Equal(decimal.RequireFromString(d.output)) {
+			t.Error("incorrect "+name+" result for ", d.args, ", need: "+d.output+", but get: "+res.String())
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	check(t, "Sqrt", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Sqrt(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "0"},
+		{[]string{"9"}, "3"},
+		{[]string{"0.0001"}, "0.01"},
+		{[]string{"2"}, "1.4142135623730950488016887242096980785697"},
+		{[]string{"0.5"}, "0.7071067811865475244008443621048490392848"},
+		{[]string{"123456789"}, "11111.1110605555554405416661433534692458784099"},
+		{[]string{"1e-30"}, "0.000000000000001"},
+	})
+
+	if _, err := decmath.Sqrt(decimal.NewFromInt(-1), 10); err == nil {
+		t.Error("incorrect Sqrt error handling")
+	}
+	res, _ := decmath.Sqrt(decimal.NewFromInt(2), 3)
+	if res.String() != "1.414" {
+		t.Error("incorrect Sqrt precision: " + res.String())
+	}
+}
+
+func TestExp(t *testing.T) {
+	check(t, "Exp", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Exp(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "1"},
+		{[]string{"1"}, "2.7182818284590452353602874713526624977572"},
+		{[]string{"-1"}, "0.3678794411714423215955237701614608674458"},
+		{[]string{"0.5"}, "1.6487212707001281468486507878141635716538"},
+		{[]string{"-20"}, "0.0000000020611536224385578279659403801558"},
+		{[]string{"100"}, "26881171418161354484126255515800135873611118.7737419224151916086152802870349095649142"},
+		{[]string{"-1000000"}, "0"},
+	})
+
+	if _, err := decmath.Exp(decimal.NewFromInt(1000000), 10); err == nil {
+		t.Error("incorrect Exp error handling")
+	}
+}
+
+func TestLn(t *testing.T) {
+	check(t, "Ln", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Ln(args[0], 40)
+	}, []TestData{
+		{[]string{"1"}, "0"},
+		{[]string{"2"}, "0.6931471805599453094172321214581765680755"},
+		{[]string{"0.5"}, "-0.6931471805599453094172321214581765680755"},
+		{[]string{"10"}, "2.3025850929940456840179914546843642076011"},
+		{[]string{"1e-20"}, "-46.051701859880913680359829093687284152022"},
+		{[]string{"1.0000001"}, "0.0000000999999950000003333333083333353333"},
+	})
+
+	for _, x := range []int64{0, -1} {
+		if _, err := decmath.Ln(decimal.NewFromInt(x), 10); err == nil {
+			t.Error("incorrect Ln error handling")
+		}
+	}
+}
+
+func TestLog(t *testing.T) {
+	check(t, "Log10", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Log10(args[0], 30)
+	}, []TestData{
+		{[]string{"1000"}, "3"},
+		{[]string{"0.001"}, "-3"},
+		{[]string{"1"}, "0"},
+		{[]string{"2"}, "0.301029995663981195213738894724"},
+		{[]string{"31.6227766"}, "1.49999999997687546047056214015"},
+	})
+
+	check(t, "Log", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Log(args[0], args[1], 30)
+	}, []TestData{
+		{[]string{"2", "1024"}, "10"},
+		{[]string{"1.0001", "2"}, "6931.818373413795355195967849999827"},
+		{[]string{"0.5", "8"}, "-3"},
+	})
+
+	for _, d := range [][2]int64{{1, 10}, {0, 10}, {-2, 10}, {10, 0}} {
+		if _, err := decmath.Log(decimal.NewFromInt(d[0]), decimal.NewFromInt(d[1]), 10); err == nil {
+			t.Error("incorrect Log error handling")
+		}
+	}
+	if _, err := decmath.Log10(decimal.Zero, 10); err == nil {
+		t.Error("incorrect Log10 error handling")
+	}
+}
+
+func TestPow(t *testing.T) {
+	check(t, "Pow", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Pow(args[0], args[1], 30)
+	}, []TestData{
+		{[]string{"2", "10"}, "1024"},
+		{[]string{"-3", "3"}, "-27"},
+		{[]string{"1.1", "3"}, "1.331"},
+		{[]string{"10", "-2"}, "0.01"},
+		{[]string{"3", "-1"}, "0.333333333333333333333333333333"},
+		{[]string{"4", "0.5"}, "2"},
+		{[]string{"4", "-0.5"}, "0.5"},
+		{[]string{"2", "1.5"}, "2.828427124746190097603377448419"},
+		{[]string{"1.5", "2.5"}, "2.755675960631075360471944584044"},
+		{[]string{"5", "5.73"}, "10118.080371595019317118681359885373"},
+		{[]string{"2", "100.1"}, "1358634273092819767285223439627.074664709618623305071341383228"},
+		{[]string{"0", "2"}, "0"},
+		{[]string{"0", "0.5"}, "0"},
+		{[]string{"0", "0"}, "1"},
+	})
+
+	for _, d := range [][2]string{{"0", "-1"}, {"0", "-0.5"}, {"-8", "0.5"}} {
+		_, err := decmath.Pow(decimal.RequireFromString(d[0]), decimal.RequireFromString(d[1]), 10)
+		if err == nil {
+			t.Error("incorrect Pow error handling for ", d)
+		}
+	}
+}
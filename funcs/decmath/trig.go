@@ -0,0 +1,257 @@
+package decmath
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxAngleOrder - limit of digits of the integer part of arguments of sin, cos and tan,
+// the reduction of a greater angle needs pi with too many digits
+const maxAngleOrder = 1000
+
+// Pi - the number pi calculated by Machin's formula pi = 16 * atan(1/5) - 4 * atan(1/239)
+func Pi(precision int32) decimal.Decimal {
+	return pi(precision + GuardDigits).Round(precision)
+}
+
+// pi - pi with at least 'places' correct digits after the decimal point
+func pi(places int32) decimal.Decimal {
+	places += 2
+	a := atanSeries(one.DivRound(decimal.NewFromInt(5), places), places)
+	b := atanSeries(one.DivRound(decimal.NewFromInt(239), places), places)
+	return a.Mul(decimal.NewFromInt(16)).Sub(b.Mul(decimal.NewFromInt(4))).Round(places)
+}
+
+// Sin - sine of x radians
+func Sin(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	places := precision + GuardDigits
+	r, err := reduceAngle(x, places)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return sinSeries(r, places).Round(precision), nil
+}
+
+// Cos - cosine of x radians
+func Cos(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	places := precision + GuardDigits
+	r, err := reduceAngle(x, places)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return cosSeries(r, places).Round(precision), nil
+}
+
+// Tan - tangent of x radians
+func Tan(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	places := precision + GuardDigits
+	r, err := reduceAngle(x, places)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	c := cosSeries(r, places)
+	if c.IsZero() {
+		return decimal.Zero, errors.New("tangent is undefined for " + x.String())
+	}
+	// the division by a small cosine increases the error
+	if lost := -order(c); lost > 0 {
+		places += 2 * lost
+		// the size of x is already checked
+		r, _ = reduceAngle(x, places)
+		c = cosSeries(r, places)
+	}
+	return sinSeries(r, places).DivRound(c, precision+GuardDigits).Round(precision), nil
+}
+
+// reduceAngle - x reduced to [-pi, pi] with at least 'places' correct digits after the decimal point.
+// An angle with more than maxAngleOrder digits of the integer part is an error
+func reduceAngle(x decimal.Decimal, places int32) (decimal.Decimal, error) {
+	if order(x) > maxAngleOrder {
+		return decimal.Zero, errors.New("too large angle, its integer part has more than " + strconv.Itoa(maxAngleOrder) + " digits")
+	}
+	// the error of pi is multiplied by x / (2 * pi)
+	p := pi(places + max32(order(x), 0) + 1)
+	twoPi := p.Mul(two)
+	if x.Abs().LessThanOrEqual(p) {
+		return x, nil
+	}
+	k := x.Add(p).DivRound(twoPi, 1).Floor()
+	return x.Sub(k.Mul(twoPi)).Round(places + 2), nil
+}
+
+// sinSeries - the series x - x^3/3! + x^5/5! - ..., |x| <= pi
+func sinSeries(x decimal.Decimal, places int32) decimal.Decimal {
+	places += 2
+	x2 := x.Mul(x).Round(places)
+	sum, term := x, x
+	eps := decimal.New(1, -places)
+	for n := int64(2); ; n += 2 {
+		term = term.Mul(x2).DivRound(decimal.NewFromInt(-n*(n+1)), places)
+		if term.Abs().LessThan(eps) {
+			break
+		}
+		sum = sum.Add(term)
+	}
+	return sum
+}
+
+// cosSeries - the series 1 - x^2/2! + x^4/4! - ..., |x| <= pi
+func cosSeries(x decimal.Decimal, places int32) decimal.Decimal {
+	places += 2
+	x2 := x.Mul(x).Round(places)
+	sum, term := one, one
+	eps := decimal.New(1, -places)
+	for n := int64(1); ; n += 2 {
+		term = term.Mul(x2).DivRound(decimal.NewFromInt(-n*(n+1)), places)
+		if term.Abs().LessThan(eps) {
+			break
+		}
+		sum = sum.Add(term)
+	}
+	return sum
+}
+
+// Atan - arctangent of x in radians, the result is in (-pi/2, pi/2)
+func Atan(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	return atanRatio(x, one, precision+GuardDigits).Round(precision), nil
+}
+
+// Atan2 - the angle in radians between the positive x axis and the point (x, y), the result is in (-pi, pi].
+// It is 0 for the point (0, 0)
+func Atan2(y, x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	return atan2(y, x, precision+GuardDigits).Round(precision), nil
+}
+
+func atan2(y, x decimal.Decimal, places int32) decimal.Decimal {
+	switch {
+	case x.IsPositive():
+		return atanRatio(y, x, places)
+	case x.IsNegative():
+		// atan2(y, x) = pi - atan2(y, -x) for y >= 0, -pi - atan2(y, -x) for y < 0
+		p := pi(places)
+		if y.IsNegative() {
+			p = p.Neg()
+		}
+		return p.Sub(atanRatio(y, x.Neg(), places))
+	case y.IsPositive():
+		return pi(places).Mul(half)
+	case y.IsNegative():
+		return pi(places).Mul(half).Neg()
+	}
+	return decimal.Zero
+}
+
+// atanRatio - atan(y / x) for x > 0. The argument of the series is always in [-1, 1],
+// so the division doesn't lose digits
+func atanRatio(y, x decimal.Decimal, places int32) decimal.Decimal {
+	if y.Abs().LessThanOrEqual(x) {
+		return atanReduced(y.DivRound(x, places+2), places)
+	}
+	// atan(t) = sign(t) * pi/2 - atan(1/t)
+	halfPi := pi(places).Mul(half)
+	if y.IsNegative() {
+		halfPi = halfPi.Neg()
+	}
+	return halfPi.Sub(atanReduced(x.DivRound(y, places+2), places))
+}
+
+// atanReduced - atan(z) for |z| <= 1. atan(z) = 2 * atan(z / (1 + sqrt(1 + z^2))),
+// the argument is halved twice, so the series converges quickly
+func atanReduced(z decimal.Decimal, places int32) decimal.Decimal {
+	places += 2
+	for i := 0; i < 2; i++ {
+		root, _ := Sqrt(one.Add(z.Mul(z)), places)
+		z = z.DivRound(one.Add(root), places)
+	}
+	return atanSeries(z, places).Mul(decimal.NewFromInt(4))
+}
+
+// atanSeries - the series z - z^3/3 + z^5/5 - ..., |z| < 1
+func atanSeries(z decimal.Decimal, places int32) decimal.Decimal {
+	places += 2
+	z2 := z.Mul(z).Round(places).Neg()
+	sum, pow := z, z
+	eps := decimal.New(1, -places)
+	for n := int64(3); ; n += 2 {
+		pow = pow.Mul(z2).Round(places)
+		term := pow.DivRound(decimal.NewFromInt(n), places)
+		if term.Abs().LessThan(eps) {
+			break
+		}
+		sum = sum.Add(term)
+	}
+	return sum
+}
+
+// Asin - arcsine of x in radians, |x| <= 1, the result is in [-pi/2, pi/2]
+func Asin(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	places := precision + GuardDigits
+	c, err := cathetus(x, places)
+	if err != nil {
+		return decimal.Zero, errors.New("arcsine of number out of [-1, 1]: " + x.String())
+	}
+	return atan2(x, c, places).Round(precision), nil
+}
+
+// Acos - arccosine of x in radians, |x| <= 1, the result is in [0, pi]
+func Acos(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	places := precision + GuardDigits
+	c, err := cathetus(x, places)
+	if err != nil {
+		return decimal.Zero, errors.New("arccosine of number out of [-1, 1]: " + x.String())
+	}
+	return atan2(c, x, places).Round(precision), nil
+}
+
+// cathetus - sqrt(1 - x^2)
+func cathetus(x decimal.Decimal, places int32) (decimal.Decimal, error) {
+	return Sqrt(one.Sub(x.Mul(x)), places+2)
+}
+
+// Sinh - hyperbolic sine of x, (e^x - e^-x) / 2
+func Sinh(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	a, b, err := expPair(x, precision+GuardDigits)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return a.Sub(b).Mul(half).Round(precision), nil
+}
+
+// Cosh - hyperbolic cosine of x, (e^x + e^-x) / 2
+func Cosh(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	a, b, err := expPair(x, precision+GuardDigits)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return a.Add(b).Mul(half).Round(precision), nil
+}
+
+// expPair - e^x and e^-x
+func expPair(x decimal.Decimal, places int32) (decimal.Decimal, decimal.Decimal, error) {
+	a, err := Exp(x, places)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	b, err := Exp(x.Neg(), places)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	return a, b, nil
+}
+
+// Tanh - hyperbolic tangent of x, sign(x) * (1 - e^-2|x|) / (1 + e^-2|x|)
+func Tanh(x decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	places := precision + GuardDigits
+	sign := decimal.NewFromInt(int64(x.Sign()))
+	// e^-2|x| is less than 10^-(places+1), so the result is 1 at the precision
+	if x.Abs().Mul(two).GreaterThan(decimal.NewFromInt(int64(places+2) * 23 / 10)) {
+		return sign, nil
+	}
+	t, err := Exp(x.Abs().Mul(two).Neg(), places+2)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return one.Sub(t).DivRound(one.Add(t), places).Mul(sign).Round(precision), nil
+}
@@ -0,0 +1,119 @@
+package decmath_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs/decmath"
+	"github.com/shopspring/decimal"
+)
+
+func TestPi(t *testing.T) {
+	if res := decmath.Pi(40); res.String() != "3.1415926535897932384626433832795028841972" {
+		t.Error("incorrect Pi: " + res.String())
+	}
+	if res := decmath.Pi(2); res.String() != "3.14" {
+		t.Error("incorrect Pi precision: " + res.String())
+	}
+}
+
+func TestTrig(t *testing.T) {
+	check(t, "Sin", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Sin(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "0"},
+		{[]string{"1"}, "0.8414709848078965066525023216302989996226"},
+		{[]string{"-1"}, "-0.8414709848078965066525023216302989996226"},
+		{[]string{"3.1415926535897932384626433832795028841971693993751"}, "0"},
+		{[]string{"7.2831853071795864769252867665590057683943387987502"}, "0.8414709848078965066525023216302989996226"},
+	})
+	check(t, "Cos", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Cos(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "1"},
+		{[]string{"1"}, "0.5403023058681397174009366074429766037323"},
+		{[]string{"-1"}, "0.5403023058681397174009366074429766037323"},
+	})
+	check(t, "Tan", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Tan(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "0"},
+		{[]string{"1"}, "1.5574077246549022305069748074583601730873"},
+		{[]string{"-1"}, "-1.5574077246549022305069748074583601730873"},
+	})
+	check(t, "Atan", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Atan(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "0"},
+		{[]string{"1"}, "0.7853981633974483096156608458198757210493"},
+		{[]string{"-1"}, "-0.7853981633974483096156608458198757210493"},
+	})
+	check(t, "Asin", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Asin(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "0"},
+		{[]string{"0.5"}, "0.5235987755982988730771072305465838140329"},
+		{[]string{"-1"}, "-1.5707963267948966192313216916397514420986"},
+	})
+	check(t, "Acos", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Acos(args[0], 40)
+	}, []TestData{
+		{[]string{"1"}, "0"},
+		{[]string{"0.5"}, "1.0471975511965977461542144610931676280657"},
+		{[]string{"-1"}, "3.1415926535897932384626433832795028841972"},
+	})
+	check(t, "Atan2", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Atan2(args[0], args[1], 40)
+	}, []TestData{
+		{[]string{"0", "0"}, "0"},
+		{[]string{"0", "-1"}, "3.1415926535897932384626433832795028841972"},
+		{[]string{"-1", "-1"}, "-2.3561944901923449288469825374596271631479"},
+		{[]string{"2", "0"}, "1.5707963267948966192313216916397514420986"},
+		{[]string{"5", "5"}, "0.7853981633974483096156608458198757210493"},
+	})
+
+	// the reduction of a too large angle needs pi with too many digits
+	for _, x := range []string{"1e1001", "-1e100000"} {
+		for name, f := range map[string]func(decimal.Decimal, int32) (decimal.Decimal, error){"Sin": decmath.Sin, "Cos": decmath.Cos, "Tan": decmath.Tan} {
+			if _, err := f(decimal.RequireFromString(x), 10); err == nil || err.Error() != "too large angle, its integer part has more than 1000 digits" {
+				t.Error("incorrect "+name+" error for "+x+": ", err)
+			}
+		}
+	}
+	if _, err := decmath.Sin(decimal.RequireFromString("1e999"), 10); err != nil {
+		t.Error("incorrect Sin error: ", err)
+	}
+
+	for _, x := range []string{"1.5", "-1.0001"} {
+		if _, err := decmath.Asin(decimal.RequireFromString(x), 10); err == nil {
+			t.Error("incorrect Asin error handling")
+		}
+		if _, err := decmath.Acos(decimal.RequireFromString(x), 10); err == nil {
+			t.Error("incorrect Acos error handling")
+		}
+	}
+}
+
+func TestHyperbolic(t *testing.T) {
+	check(t, "Sinh", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Sinh(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "0"},
+		{[]string{"1"}, "1.1752011936438014568823818505956008151557"},
+		{[]string{"-1"}, "-1.1752011936438014568823818505956008151557"},
+	})
+	check(t, "Cosh", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Cosh(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "1"},
+		{[]string{"1"}, "1.5430806348152437784779056207570616826015"},
+	})
+	check(t, "Tanh", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decmath.Tanh(args[0], 40)
+	}, []TestData{
+		{[]string{"0"}, "0"},
+		{[]string{"1"}, "0.7615941559557648881194582826047935904128"},
+		{[]string{"-1"}, "-0.7615941559557648881194582826047935904128"},
+		{[]string{"1000"}, "1"},
+		{[]string{"-1000"}, "-1"},
+	})
+}
@@ -0,0 +1,38 @@
+package funcs
+
+import "github.com/shopspring/decimal"
+
+// LazyArg - an unevaluated argument of a lazy function.
+// Arguments built from a parsed expression also have the method
+// Expression() interfaces.Expression, which returns the argument tree
+type LazyArg interface {
+	// Evaluate - evaluate the argument in the scope of the call
+	Evaluate() (decimal.Decimal, error)
+	String() string
+}
+
+// LazyFuncType - type of functions which receive unevaluated arguments,
+// so an argument which is not needed is never evaluated
+type LazyFuncType func(args ...LazyArg) (decimal.Decimal, error)
+
+// Eager - adapter to call the lazy function with already evaluated arguments
+func (f LazyFuncType) Eager() FuncType {
+	return func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		lazyArgs := make([]LazyArg, len(args))
+		for i, arg := range args {
+			lazyArgs[i] = valueArg(arg)
+		}
+		return f(lazyArgs...)
+	}
+}
+
+// valueArg - already evaluated argument
+type valueArg decimal.Decimal
+
+func (v valueArg) Evaluate() (decimal.Decimal, error) {
+	return decimal.Decimal(v), nil
+}
+
+func (v valueArg) String() string {
+	return decimal.Decimal(v).String()
+}
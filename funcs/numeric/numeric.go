@@ -0,0 +1,185 @@
+// Package numeric - rounding, comparison, modulo and percentage functions.
+// They are not registered by default, call Register to add them to a parser:
+//
+//	numeric.Register(parser.Functions())
+package numeric
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+// maxPlaces - limit of decimal places of the rounding functions
+const maxPlaces = 10000
+
+var (
+	hundred = decimal.NewFromInt(100)
+
+	roundSignature = funcs.Signature{Params: []funcs.Kind{funcs.KindNumber, funcs.KindNumber, funcs.KindString}, Result: funcs.KindNumber}
+
+	// Functions - metadata of the numeric functions. min and max are the built-in aggregate functions
+	Functions = []funcs.Descriptor{
+		{Name: "round", Value: Round, MinArgs: 1, MaxArgs: 3, Description: "round(x, places, mode) - x rounded by the mode, places is 0 by default. " +
+			"The mode is \"half-up\" (half away from zero) by default, \"half-even\", \"down\", \"ceiling\" or \"floor\"", Pure: true,
+			Signature: &roundSignature},
+		{Name: "floor", Func: Floor, MinArgs: 1, MaxArgs: 2, Description: "floor(x, places) - x rounded towards -infinity, places is 0 by default", Pure: true},
+		{Name: "ceil", Func: Ceil, MinArgs: 1, MaxArgs: 2, Description: "ceil(x, places) - x rounded towards +infinity, places is 0 by default", Pure: true},
+		{Name: "trunc", Func: Trunc, MinArgs: 1, MaxArgs: 2, Description: "trunc(x, places) - x rounded towards zero, places is 0 by default", Pure: true},
+		{Name: "sign", Func: Sign, MinArgs: 1, MaxArgs: 1, Description: "sign(x) - -1, 0 or 1 by the sign of x", Pure: true},
+		{Name: "clamp", Func: Clamp, MinArgs: 3, MaxArgs: 3, Description: "clamp(x, lo, hi) - x limited to the range [lo, hi]", Pure: true},
+		{Name: "mod", Func: Mod, MinArgs: 2, MaxArgs: 2, Description: "mod(a, b) - floored remainder of a / b, it has the sign of b: mod(-7, 3) = 2", Pure: true},
+		{Name: "rem", Func: Rem, MinArgs: 2, MaxArgs: 2, Description: "rem(a, b) - truncated remainder of a / b, it has the sign of a like %: rem(-7, 3) = -1", Pure: true},
+		{Name: "percent", Func: Percent, MinArgs: 2, MaxArgs: 2, Description: "percent(x, p) - p percent of x", Pure: true},
+		{Name: "percent_of", WithSettings: PercentOf, MinArgs: 2, MaxArgs: 2, Description: "percent_of(part, whole) - part as a percentage of whole", Pure: true},
+		{Name: "percent_change", WithSettings: PercentChange, MinArgs: 2, MaxArgs: 2, Description: "percent_change(old, new) - change from old to new in percent", Pure: true},
+	}
+)
+
+// Register - add the numeric functions to the registry, existing functions with the same names are overridden
+func Register(r *funcs.FunctionRegistry) error {
+	for _, d := range Functions {
+		if err := r.RegisterFunction(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Round - round(x, places, mode) rounds x by the rounding mode named like "half-even",
+// without the mode x is rounded half away from zero
+func Round(_ funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return funcs.Null, errors.New("incorrect count of args for 'round' function. Need: 1..3, but get: " + strconv.Itoa(len(args)))
+	}
+	mode := funcs.RoundHalfUp
+	if len(args) == 3 {
+		name, err := args[2].AsString()
+		if err != nil {
+			return funcs.Null, funcs.DescribeTypeError(err, "argument 3 of 'round'")
+		}
+		if mode, err = funcs.ParseRoundingMode(name); err != nil {
+			return funcs.Null, errors.New("incorrect argument 3 of 'round' function: " + err.Error())
+		}
+		args = args[:2]
+	}
+	nums, err := funcs.NumberArgs("round", args)
+	if err != nil {
+		return funcs.Null, err
+	}
+	res, err := round("'round' function", mode, nums)
+	if err != nil {
+		return funcs.Null, err
+	}
+	return funcs.NumberValue(res), nil
+}
+
+// Floor - floor(x, places) rounds x towards -infinity
+func Floor(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return round("'floor' function", funcs.RoundFloor, args)
+}
+
+// Ceil - ceil(x, places) rounds x towards +infinity
+func Ceil(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return round("'ceil' function", funcs.RoundCeiling, args)
+}
+
+// Trunc - trunc(x, places) rounds x towards zero
+func Trunc(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return round("'trunc' function", funcs.RoundDown, args)
+}
+
+// round - round args[0] to args[1] decimal places by the mode, places may be negative: round(1234, -2) = 1200
+func round(what string, mode funcs.RoundingMode, args []decimal.Decimal) (decimal.Decimal, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return decimal.Zero, errors.New("incorrect count of args for " + what + ". Need: 1..2, but get: " + strconv.Itoa(len(args)))
+	}
+	s := funcs.Settings{Rounding: mode}
+	if len(args) == 2 {
+		places := args[1]
+		if !places.IsInteger() || places.Abs().GreaterThan(decimal.NewFromInt(maxPlaces)) {
+			return decimal.Zero, errors.New("incorrect count of decimal places for " + what + ": " + places.String())
+		}
+		s.Precision = int32(places.IntPart())
+	}
+	return s.Round(args[0]), nil
+}
+
+// Sign - sign(x) is -1, 0 or 1
+func Sign(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'sign' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromInt(int64(args[0].Sign())), nil
+}
+
+// Clamp - clamp(x, lo, hi) is lo if x < lo, hi if x > hi, otherwise x
+func Clamp(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'clamp' function", 3, args); err != nil {
+		return decimal.Zero, err
+	}
+	x, lo, hi := args[0], args[1], args[2]
+	if lo.GreaterThan(hi) {
+		return decimal.Zero, errors.New("incorrect range for 'clamp' function: " + lo.String() + " > " + hi.String())
+	}
+	return decimal.Min(decimal.Max(x, lo), hi), nil
+}
+
+// Mod - mod(a, b) is the floored remainder, it has the sign of b
+func Mod(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'mod' function", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	if args[1].IsZero() {
+		return decimal.Zero, errors.New("incorrect divisor for 'mod' function")
+	}
+	r := args[0].Mod(args[1])
+	if !r.IsZero() && r.Sign() != args[1].Sign() {
+		r = r.Add(args[1])
+	}
+	return r, nil
+}
+
+// Rem - rem(a, b) is the truncated remainder, it has the sign of a
+func Rem(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'rem' function", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	if args[1].IsZero() {
+		return decimal.Zero, errors.New("incorrect divisor for 'rem' function")
+	}
+	return args[0].Mod(args[1]), nil
+}
+
+// Percent - percent(x, p) is x * p / 100, it is exact
+func Percent(args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'percent' function", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	return args[0].Mul(args[1]).Shift(-2), nil
+}
+
+// PercentOf - percent_of(part, whole) is part * 100 / whole rounded by the settings
+func PercentOf(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'percent_of' function", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	if args[1].IsZero() {
+		return decimal.Zero, errors.New("incorrect whole for 'percent_of' function: 0")
+	}
+	return s.Div(args[0].Mul(hundred), args[1])
+}
+
+// PercentChange - percent_change(old, new) is (new - old) * 100 / |old| rounded by the settings,
+// so a growth is positive even if old is negative
+func PercentChange(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'percent_change' function", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	if args[0].IsZero() {
+		return decimal.Zero, errors.New("incorrect old value for 'percent_change' function: 0")
+	}
+	return s.Div(args[1].Sub(args[0]).Mul(hundred), args[0].Abs())
+}
@@ -0,0 +1,139 @@
+package numeric_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/funcs/numeric"
+	"github.com/arconomy/go-math-expression-parser/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestNumericFunctions(t *testing.T) {
+	type TestData struct {
+		exp string
+		res string
+	}
+
+	p := parser.NewParser()
+	if err := numeric.Register(p.Functions()); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []TestData{
+		{"round(2.5)", "3"},
+		{"round(-2.5)", "-3"},
+		{"round(2.345, 2)", "2.35"},
+		{"round(1234, -2)", "1200"},
+		{`round(2.345, 2, "half-up")`, "2.35"},
+		{`round(-2.5, 0, "half-up")`, "-3"},
+		{`round(2.345, 2, "half-even")`, "2.34"},
+		{`round(2.355, 2, "half-even")`, "2.36"},
+		{`round(-2.789, 2, "down")`, "-2.78"},
+		{`round(2.701, 2, "ceiling")`, "2.71"},
+		{`round(-2.1, 0, "ceiling")`, "-2"},
+		{`round(2.789, 1, "floor")`, "2.7"},
+		{`round(-2.1, 0, "floor")`, "-3"},
+		{`round(1250, -2, "half-even")`, "1200"},
+		{`round(2.5, 0, "half-even")`, "2"},
+		{"floor(2.7)", "2"},
+		{"floor(-2.1)", "-3"},
+		{"floor(2.789, 1)", "2.7"},
+		{"ceil(2.1)", "3"},
+		{"ceil(-2.7)", "-2"},
+		{"ceil(2.701, 2)", "2.71"},
+		{"trunc(2.7)", "2"},
+		{"trunc(-2.7)", "-2"},
+		{"trunc(-2.789, 2)", "-2.78"},
+		{"sign(-0.5)", "-1"},
+		{"sign(0)", "0"},
+		{"sign(12)", "1"},
+		{"min(3)", "3"},
+		{"min(3, -1, 2)", "-1"},
+		{"max(3, -1, 2)", "3"},
+		{"clamp(5, 0, 10)", "5"},
+		{"clamp(-5, 0, 10)", "0"},
+		{"clamp(15, 0, 10)", "10"},
+		{"mod(7, 3)", "1"},
+		{"mod(-7, 3)", "2"},
+		{"mod(7, -3)", "-2"},
+		{"mod(-7, -3)", "-1"},
+		{"mod(6, -3)", "0"},
+		{"rem(-7, 3)", "-1"},
+		{"rem(7, -3)", "1"},
+		{"percent(200, 15)", "30"},
+		{"percent(19.99, 7.5)", "1.49925"},
+		{"percent_of(30, 200)", "15"},
+		{"percent_of(1, 3)", "33.3333333333333333"},
+		{"percent_change(80, 100)", "25"},
+		{"percent_change(100, 80)", "-20"},
+		{"percent_change(-50, -25)", "50"},
+	}
+
+	for _, d := range data {
+		exp, err := p.Parse(d.exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := exp.Evaluate(nil)
+		if err != nil {
+			t.Error(d.exp + ": " + err.Error())
+		} else if !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect result of " + d.exp + ", need: " + d.res + ", but get: " + res.String())
+		}
+	}
+}
+
+func TestNumericFunctionsErrors(t *testing.T) {
+	p := parser.NewParser()
+	if err := numeric.Register(p.Functions()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"round(1, 0.5)", `round(1, 0, "up")`, `round(1, 0.5, "floor")`, "round(1, 2, 3)", "floor(1, 100000)", "clamp(1, 10, 0)", "mod(1, 0)", "rem(1, 0)",
+		"percent_of(1, 0)", "percent_change(0, 1)"} {
+		exp, err := p.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := exp.Evaluate(nil); err == nil {
+			t.Error("incorrect error handling: " + s)
+		}
+	}
+
+	// the count of arguments is checked at parsing time
+	for _, s := range []string{"round()", `round(1, 2, "floor", 3)`, "min()", "clamp(1, 2)", "sign(1, 2)"} {
+		if _, err := p.Parse(s); err == nil {
+			t.Error("incorrect count of args was not handled: " + s)
+		}
+	}
+	exp, err := p.Parse("round(x, 2, 3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := exp.CheckTypes(map[string]funcs.Kind{"x": funcs.KindNumber}); len(errs) != 1 {
+		t.Error("incorrect type checking of the rounding mode")
+	}
+	if _, err := numeric.Round(funcs.DefaultSettings); err == nil || err.Error() != "incorrect count of args for 'round' function. Need: 1..3, but get: 0" {
+		t.Error("incorrect Round error handling")
+	}
+}
+
+func TestNumericFunctionsSettings(t *testing.T) {
+	p := parser.NewParser()
+	if err := numeric.Register(p.Functions()); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetSettings(funcs.Settings{Precision: 1, Rounding: funcs.RoundFloor}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err := p.Parse("percent_of(2, 3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := exp.Evaluate(nil)
+	if err != nil || !res.Equal(decimal.RequireFromString("66.6")) {
+		t.Error("incorrect percent_of result with settings: " + res.String())
+	}
+}
@@ -0,0 +1,207 @@
+package funcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Object - the value with named members, like the order of 'order.total'.
+// A Go type can implement it to give access to its fields without reflection
+type Object interface {
+	// Member - the value of the member, ok is false if the object has no such member
+	Member(name string) (val Value, ok bool)
+}
+
+// ObjectValue - the object value
+func ObjectValue(o Object) Value {
+	return Value{kind: KindObject, obj: o}
+}
+
+// AsObject - the object, other types are a *TypeError
+func (v Value) AsObject() (Object, error) {
+	if v.kind != KindObject {
+		return nil, &TypeError{What: "value", Need: KindObject, Get: v.kind}
+	}
+	return v.obj, nil
+}
+
+// MapObject - the object with the members of the map
+type MapObject map[string]Value
+
+// Member - the value of the key name
+func (m MapObject) Member(name string) (Value, bool) {
+	val, ok := m[name]
+	return val, ok
+}
+
+// String - the members sorted by names, like '{currency: "USD", total: 10}'
+func (m MapObject) String() string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	strs := make([]string, len(names))
+	for i, name := range names {
+		strs[i] = name + ": " + m[name].String()
+	}
+	return "{" + strings.Join(strs, ", ") + "}"
+}
+
+// ValueOf - convert the Go value to Value: nil is null, numbers, decimal.Decimal and json.Number are numbers,
+// slices and arrays are lists, maps with string keys and structs are objects, pointers are dereferenced.
+// The fields of a struct are named by their json tags, like encoding/json does. Objects and Values are kept as is.
+// The value must not have cycles
+func ValueOf(x interface{}) (Value, error) {
+	switch x := x.(type) {
+	case nil:
+		return Null, nil
+	case Value:
+		return x, nil
+	case Object:
+		return ObjectValue(x), nil
+	case decimal.Decimal:
+		return NumberValue(x), nil
+	case json.Number:
+		num, err := decimal.NewFromString(string(x))
+		if err != nil {
+			return Null, errors.New("incorrect number '" + string(x) + "'")
+		}
+		return NumberValue(num), nil
+	}
+
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return Null, nil
+		}
+		return ValueOf(v.Elem().Interface())
+	case reflect.Bool:
+		return BoolValue(v.Bool()), nil
+	case reflect.String:
+		return StringValue(v.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NumberValue(decimal.NewFromInt(v.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return NumberValue(decimal.RequireFromString(strconv.FormatUint(v.Uint(), 10))), nil
+	case reflect.Float32:
+		return NumberValue(decimal.NewFromFloat32(float32(v.Float()))), nil
+	case reflect.Float64:
+		return NumberValue(decimal.NewFromFloat(v.Float())), nil
+	case reflect.Slice, reflect.Array:
+		items := make([]Value, v.Len())
+		for i := range items {
+			item, err := ValueOf(v.Index(i).Interface())
+			if err != nil {
+				return Null, err
+			}
+			items[i] = item
+		}
+		return ListValue(items...), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return Null, errors.New("unsupported type of value: " + v.Type().String() + ", the keys must be strings")
+		}
+		obj := make(MapObject, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			val, err := ValueOf(iter.Value().Interface())
+			if err != nil {
+				return Null, err
+			}
+			obj[iter.Key().String()] = val
+		}
+		return ObjectValue(obj), nil
+	case reflect.Struct:
+		return structValue(v)
+	}
+	return Null, errors.New("unsupported type of value: " + v.Type().String())
+}
+
+// structValue - the object with the exported fields of the struct, including the fields of embedded structs
+func structValue(v reflect.Value) (Value, error) {
+	obj := make(MapObject)
+	for _, f := range reflect.VisibleFields(v.Type()) {
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		field, err := v.FieldByIndexErr(f.Index)
+		if err != nil || !field.CanInterface() {
+			// the field of a nil embedded pointer or of an unexported embedded struct
+			continue
+		}
+		val, err := ValueOf(field.Interface())
+		if err != nil {
+			return Null, err
+		}
+		obj[name] = val
+	}
+	return ObjectValue(obj), nil
+}
+
+// JSONValue - the value of the JSON document, its numbers are converted without float64
+func JSONValue(data []byte) (Value, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		return Null, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return Null, errors.New("unexpected data after the JSON value")
+	}
+	return ValueOf(x)
+}
+
+// ValuesOf - convert the Go values of the variables by ValueOf
+func ValuesOf(vars map[string]interface{}) (map[string]Value, error) {
+	res := make(map[string]Value, len(vars))
+	for name, x := range vars {
+		val, err := ValueOf(x)
+		if err != nil {
+			return nil, errors.New("incorrect variable '" + name + "': " + err.Error())
+		}
+		res[name] = val
+	}
+	return res, nil
+}
+
+// sameObject - checks that a and b are the same object, objects of types without == are compared by their address
+func sameObject(a, b Object) bool {
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) {
+		return false
+	}
+	if ta.Comparable() {
+		return a == b
+	}
+	switch ta.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Func:
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+	return false
+}
+
+// objectString - the string of the object if it has the String method
+func objectString(o Object) string {
+	if s, ok := o.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return "object"
+}
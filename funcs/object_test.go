@@ -0,0 +1,116 @@
+package funcs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+type testTier struct {
+	Discount float64 `json:"discount,omitempty"`
+	Name     string
+}
+
+type testPerson struct {
+	Email string `json:"-"`
+	age   int
+}
+
+type testCustomer struct {
+	*testPerson
+	Tier  *testTier `json:"tier"`
+	Tags  []string
+	Notes map[string]int
+}
+
+// testAccessor - the object which gives its members without reflection
+type testAccessor struct {
+	calls int
+}
+
+func (a *testAccessor) Member(name string) (funcs.Value, bool) {
+	a.calls++
+	return funcs.StringValue(name), name != "missing"
+}
+
+func TestValueOf(t *testing.T) {
+	accessor := &testAccessor{}
+	type TestData struct {
+		x    interface{}
+		kind funcs.Kind
+		str  string
+	}
+	data := []TestData{
+		{nil, funcs.KindNull, "null"},
+		{(*testTier)(nil), funcs.KindNull, "null"},
+		{funcs.StringValue("a"), funcs.KindString, `"a"`},
+		{decimal.RequireFromString("1.50"), funcs.KindNumber, "1.5"},
+		{json.Number("12.25"), funcs.KindNumber, "12.25"},
+		{int8(-3), funcs.KindNumber, "-3"},
+		{uint64(18446744073709551615), funcs.KindNumber, "18446744073709551615"},
+		{0.1, funcs.KindNumber, "0.1"},
+		{float32(0.1), funcs.KindNumber, "0.1"},
+		{true, funcs.KindBool, "true"},
+		{[]interface{}{1, "a", nil}, funcs.KindList, `[1, "a", null]`},
+		{[2]int{1, 2}, funcs.KindList, "[1, 2]"},
+		{map[string]interface{}{"b": 1, "a": []int{}}, funcs.KindObject, "{a: [], b: 1}"},
+		{testCustomer{testPerson: &testPerson{Email: "a@b.c", age: 30}, Tier: &testTier{Discount: 0.1, Name: "gold"}},
+			funcs.KindObject, `{Notes: {}, Tags: [], tier: {Name: "gold", discount: 0.1}}`},
+		{&testCustomer{}, funcs.KindObject, "{Notes: {}, Tags: [], tier: null}"},
+		{accessor, funcs.KindObject, "object"},
+	}
+	for _, d := range data {
+		val, err := funcs.ValueOf(d.x)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if val.Kind() != d.kind || val.String() != d.str {
+			t.Error("incorrect value: " + val.String() + " (" + val.Kind().String() + "), need: " + d.str)
+		}
+		if !val.Equal(val) {
+			t.Error("value is not equal to itself: " + val.String())
+		}
+	}
+
+	obj, _ := funcs.ObjectValue(accessor).AsObject()
+	if val, ok := obj.Member("x"); !ok || !val.Equal(funcs.StringValue("x")) || accessor.calls != 1 {
+		t.Error("incorrect member of the accessor: ", val)
+	}
+	a, _ := funcs.ValueOf(map[string]int{"a": 1})
+	b, _ := funcs.ValueOf(map[string]int{"a": 1})
+	if a.Equal(b) || a.Equal(funcs.ObjectValue(accessor)) {
+		t.Error("different objects are equal")
+	}
+
+	if _, err := funcs.ValueOf(map[int]int{}); err == nil || err.Error() != "unsupported type of value: map[int]int, the keys must be strings" {
+		t.Error("incorrect error: ", err)
+	}
+	if _, err := funcs.ValueOf([]interface{}{make(chan int)}); err == nil || err.Error() != "unsupported type of value: chan int" {
+		t.Error("incorrect error: ", err)
+	}
+	if _, err := funcs.ValuesOf(map[string]interface{}{"x": 1, "f": func() {}}); err == nil || err.Error() != "incorrect variable 'f': unsupported type of value: func()" {
+		t.Error("incorrect error: ", err)
+	}
+	if _, err := funcs.NumberValue(decimal.Zero).AsObject(); err == nil || err.Error() != "incorrect type of value. Need: object, but get: number" {
+		t.Error("incorrect error: ", err)
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	val, err := funcs.JSONValue([]byte(`{"total": 0.30000000000000004, "lines": [{"qty": 2}], "note": null, "vip": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val.String() != `{lines: [{qty: 2}], note: null, total: 0.30000000000000004, vip: true}` {
+		t.Error("incorrect value: " + val.String())
+	}
+
+	for _, s := range []string{`{"a": 1} {}`, `{"a": `, ``} {
+		if _, err := funcs.JSONValue([]byte(s)); err == nil {
+			t.Error("error was not handled for '" + s + "'")
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package funcs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Fixity - the position of an operator relative to its operands
+type Fixity int
+
+const (
+	Infix   Fixity = iota // 'a // b'
+	Prefix                // '-a'
+	Postfix               // 'n!'
+)
+
+// DefaultPrefixLevel - the level of prefix operators registered without RegisterOperator, like '-' and '!'
+const DefaultPrefixLevel = LevelMultiplicative
+
+// Operator - the descriptor of a binary, prefix or postfix operator.
+//
+// Level is the priority level. A binary operator has one of levels LevelPower..LevelOr.
+// The operand of a prefix operator contains binary operators with lower levels only,
+// so '-' with DefaultPrefixLevel makes '-2^2' be '-(2^2)' and '-2*3' be '(-2)*3'.
+// A postfix operator applies to the result of binary operators with lower levels only,
+// LevelUnary binds tightest: '2^3!' is '2^(3!)'
+type Operator struct {
+	Name          string
+	Fixity        Fixity
+	Level         int
+	Associativity Associativity // of a binary operator
+	Func          FuncType      // gets one argument or, for a binary operator, two
+	Signature     *Signature    // optional types of the operands and the result for the type checker
+}
+
+// Validate - checks that the operator can be registered
+func (op Operator) Validate() error {
+	if op.Name == "" {
+		return errors.New("operator name is empty")
+	}
+	if isName(op.Name) {
+		return errors.New("operator name must not be a name of a function or a variable: '" + op.Name + "'")
+	}
+	if unicode.IsDigit(rune(op.Name[0])) || op.Name[0] == '.' ||
+		strings.ContainsAny(op.Name, "()[],?:\";=") || strings.Contains(op.Name, "->") || strings.IndexFunc(op.Name, unicode.IsSpace) >= 0 {
+		return errors.New("incorrect operator name: '" + op.Name + "'")
+	}
+	if op.Func == nil {
+		return errors.New("operator '" + op.Name + "' has no function")
+	}
+
+	minLevel := LevelUnary
+	switch op.Fixity {
+	case Infix:
+		minLevel = LevelPower
+	case Prefix, Postfix:
+	default:
+		return errors.New("incorrect fixity of operator '" + op.Name + "': " + strconv.Itoa(int(op.Fixity)))
+	}
+	if op.Level < minLevel || op.Level >= LevelsOfPriorities {
+		return errors.New("incorrect level of operator '" + op.Name + "': " + strconv.Itoa(op.Level))
+	}
+	if op.Associativity != LeftAssoc && op.Associativity != RightAssoc {
+		return errors.New("incorrect associativity of operator '" + op.Name + "'")
+	}
+	return nil
+}
+
+// isName - checks that s looks like a name of a function or a variable, which can't be an operator
+func isName(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
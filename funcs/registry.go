@@ -0,0 +1,477 @@
+package funcs
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// FunctionRegistry - concurrency-safe set of functions and operators split by priority levels.
+// Clones share the same table until one of them is changed (copy-on-write),
+// so cloning is cheap and changes of a clone never affect the source registry
+type FunctionRegistry struct {
+	mu    sync.Mutex // serializes writers, readers don't lock
+	table atomic.Pointer[functionTable]
+}
+
+// functionTable - must not be changed after it is published in a registry
+type functionTable struct {
+	levels  [LevelsOfPriorities]map[string]entry
+	assoc   map[string]Associativity // binary operators which are not left-associative
+	prefix  map[string]int           // levels of prefix operators from levels[0] registered by RegisterOperator
+	postfix map[string]Operator      // postfix operators
+}
+
+// entry - the function registered on a priority level with all its versions,
+// registering the name again replaces the whole entry
+type entry struct {
+	fn         FuncType          // the version over numbers with DefaultSettings
+	lazy       LazyFuncType      // the lazy version
+	settings   SettingsFuncType  // the version which depends on the settings
+	value      ValueFuncType     // the version over typed values
+	lazyValue  LazyValueFuncType // the version over typed values with lazy arguments
+	signature  *Signature        // declared types for the type checker
+	descriptor *Descriptor       // metadata of a function from levels[0]
+}
+
+// NewFunctionRegistry - create a registry with a copy of the operators
+func NewFunctionRegistry(operators [LevelsOfPriorities]map[string]FuncType) *FunctionRegistry {
+	r := new(FunctionRegistry)
+	var t functionTable
+	for i := range operators {
+		t.levels[i] = make(map[string]entry, len(operators[i]))
+		for key, f := range operators[i] {
+			t.levels[i][key] = entry{fn: f}
+		}
+	}
+	t.assoc = make(map[string]Associativity)
+	t.prefix = make(map[string]int)
+	t.postfix = make(map[string]Operator)
+	r.table.Store(&t)
+	return r
+}
+
+func (t *functionTable) copy() functionTable {
+	var res functionTable
+	for i := range t.levels {
+		res.levels[i] = make(map[string]entry, len(t.levels[i]))
+		for key, e := range t.levels[i] {
+			res.levels[i][key] = e
+		}
+	}
+	res.assoc = make(map[string]Associativity, len(t.assoc))
+	for key, a := range t.assoc {
+		res.assoc[key] = a
+	}
+	res.prefix = make(map[string]int, len(t.prefix))
+	for key, level := range t.prefix {
+		res.prefix[key] = level
+	}
+	res.postfix = make(map[string]Operator, len(t.postfix))
+	for key, op := range t.postfix {
+		res.postfix[key] = op
+	}
+	return res
+}
+
+// Clone - return an independent registry with the same functions
+func (r *FunctionRegistry) Clone() *FunctionRegistry {
+	c := new(FunctionRegistry)
+	c.table.Store(r.load())
+	return c
+}
+
+// Lookup - return the function registered on the priority level
+func (r *FunctionRegistry) Lookup(level int, name string) (FuncType, bool) {
+	if level < 0 || level >= LevelsOfPriorities {
+		return nil, false
+	}
+	e, ok := r.load().levels[level][name]
+	return e.fn, ok
+}
+
+// Register - add the function to the priority level or override the existing one.
+// The function has no metadata, so its arguments are not checked at parsing time. An unknown level is an error
+func (r *FunctionRegistry) Register(level int, name string, f FuncType) error {
+	if err := checkLevel(level); err != nil {
+		return err
+	}
+	r.update(func(t *functionTable) {
+		t.levels[level][name] = entry{fn: f}
+	})
+	return nil
+}
+
+// RegisterLazy - add the function with lazy arguments to the priority level or override the existing one
+func (r *FunctionRegistry) RegisterLazy(level int, name string, f LazyFuncType) error {
+	if err := checkLevel(level); err != nil {
+		return err
+	}
+	r.update(func(t *functionTable) {
+		t.levels[level][name] = entry{fn: f.Eager(), lazy: f}
+	})
+	return nil
+}
+
+// RegisterWithSettings - add the function which depends on the arithmetic settings
+// to the priority level or override the existing one
+func (r *FunctionRegistry) RegisterWithSettings(level int, name string, f SettingsFuncType) error {
+	if err := checkLevel(level); err != nil {
+		return err
+	}
+	r.update(func(t *functionTable) {
+		t.levels[level][name] = entry{fn: f.Bind(DefaultSettings), settings: f}
+	})
+	return nil
+}
+
+// RegisterValue - add the function over typed values to the priority level or override the existing one
+func (r *FunctionRegistry) RegisterValue(level int, name string, f ValueFuncType) error {
+	if err := checkLevel(level); err != nil {
+		return err
+	}
+	r.update(func(t *functionTable) {
+		t.levels[level][name] = entry{fn: f.Numeric(DefaultSettings), value: f}
+	})
+	return nil
+}
+
+// RegisterLazyValue - add the function over typed values with lazy arguments to the priority level
+// or override the existing one
+func (r *FunctionRegistry) RegisterLazyValue(level int, name string, f LazyValueFuncType) error {
+	if err := checkLevel(level); err != nil {
+		return err
+	}
+	r.update(func(t *functionTable) {
+		lazy := f.Numeric(DefaultSettings)
+		t.levels[level][name] = entry{fn: lazy.Eager(), lazy: lazy, lazyValue: f}
+	})
+	return nil
+}
+
+// LookupValue - return the function over typed values registered on the priority level and bound to s.
+// A function over numbers is adapted: its arguments must be numbers or bools (1 and 0), otherwise it fails with *TypeError
+func (r *FunctionRegistry) LookupValue(level int, name string, s Settings) (BoundValueFunc, bool) {
+	if level < 0 || level >= LevelsOfPriorities {
+		return nil, false
+	}
+	if e := r.load().levels[level][name]; e.value != nil {
+		return e.value.Bind(s), true
+	}
+	f, ok := r.LookupWithSettings(level, name, s)
+	if !ok {
+		return nil, false
+	}
+	return func(args ...Value) (Value, error) {
+		nums, err := NumberArgs(name, args)
+		if err != nil {
+			return Null, err
+		}
+		res, err := f(nums...)
+		if err != nil {
+			return Null, err
+		}
+		return NumberValue(res), nil
+	}, true
+}
+
+// SetSignature - declare the types of the function registered on the priority level for the type checker,
+// return false if there is no function. The signature is dropped when the function is registered again
+func (r *FunctionRegistry) SetSignature(level int, name string, sig Signature) bool {
+	if _, ok := r.Lookup(level, name); !ok {
+		return false
+	}
+	r.update(func(t *functionTable) {
+		// the function can be removed by another writer after the check
+		if e, ok := t.levels[level][name]; ok {
+			e.signature = &sig
+			t.levels[level][name] = e
+		}
+	})
+	return true
+}
+
+// Signature - the types of the function registered on the priority level. A function without a declared signature
+// has NumericSignature if it works with numbers and AnySignature if it works with typed values
+func (r *FunctionRegistry) Signature(level int, name string) (Signature, bool) {
+	if level < 0 || level >= LevelsOfPriorities {
+		return Signature{}, false
+	}
+	e, ok := r.load().levels[level][name]
+	switch {
+	case !ok:
+		return Signature{}, false
+	case e.signature != nil:
+		return *e.signature, true
+	case e.value != nil || e.lazyValue != nil:
+		return AnySignature, true
+	}
+	return NumericSignature, true
+}
+
+// LookupLazyValue - return the function over typed values with lazy arguments registered on the priority level
+// and bound to s. A lazy function over numbers is adapted, its result is a number
+func (r *FunctionRegistry) LookupLazyValue(level int, name string, s Settings) (BoundLazyValueFunc, bool) {
+	if level < 0 || level >= LevelsOfPriorities {
+		return nil, false
+	}
+	e := r.load().levels[level][name]
+	if e.lazyValue != nil {
+		return e.lazyValue.Bind(s), true
+	}
+	f := e.lazy
+	if f == nil {
+		return nil, false
+	}
+	return func(args ...LazyValueArg) (Value, error) {
+		lazyArgs := make([]LazyArg, len(args))
+		for i, arg := range args {
+			lazyArgs[i] = arg
+		}
+		res, err := f(lazyArgs...)
+		if err != nil {
+			return Null, err
+		}
+		return NumberValue(res), nil
+	}, true
+}
+
+// LookupWithSettings - return the function registered on the priority level,
+// a function which depends on the arithmetic settings is bound to s
+func (r *FunctionRegistry) LookupWithSettings(level int, name string, s Settings) (FuncType, bool) {
+	if level < 0 || level >= LevelsOfPriorities {
+		return nil, false
+	}
+	e, ok := r.load().levels[level][name]
+	switch {
+	case e.settings != nil:
+		return e.settings.Bind(s), true
+	case e.value != nil:
+		return e.value.Numeric(s), true
+	}
+	return e.fn, ok
+}
+
+// LookupLazy - return the function with lazy arguments registered on the priority level
+func (r *FunctionRegistry) LookupLazy(level int, name string) (LazyFuncType, bool) {
+	if level < 0 || level >= LevelsOfPriorities {
+		return nil, false
+	}
+	f := r.load().levels[level][name].lazy
+	return f, f != nil
+}
+
+// RegisterFunction - add the function with its metadata or override the existing one
+func (r *FunctionRegistry) RegisterFunction(d Descriptor) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	e := entry{fn: d.Eager(), lazy: d.Lazy, settings: d.WithSettings, value: d.Value, lazyValue: d.LazyValue,
+		signature: d.Signature, descriptor: &d}
+	if d.LazyValue != nil {
+		e.lazy = d.LazyValue.Numeric(DefaultSettings)
+	}
+	r.update(func(t *functionTable) {
+		t.levels[0][d.Name] = e
+	})
+	return nil
+}
+
+// Describe - return metadata of the function. A function registered without metadata
+// is described as variadic
+func (r *FunctionRegistry) Describe(name string) (Descriptor, bool) {
+	e, ok := r.load().levels[0][name]
+	switch {
+	case !ok:
+		return Descriptor{}, false
+	case e.descriptor != nil:
+		return *e.descriptor, true
+	}
+	return Descriptor{Name: name, Func: e.fn, Variadic: true}, true
+}
+
+// Descriptors - metadata of all functions and unary operators sorted by name
+func (r *FunctionRegistry) Descriptors() []Descriptor {
+	var res []Descriptor
+	for _, name := range r.Names(0) {
+		d, _ := r.Describe(name)
+		res = append(res, d)
+	}
+	return res
+}
+
+// Remove - delete the function from the priority level, return false if it was not registered
+func (r *FunctionRegistry) Remove(level int, name string) bool {
+	if _, ok := r.Lookup(level, name); !ok {
+		return false
+	}
+	r.update(func(t *functionTable) {
+		delete(t.levels[level], name)
+		if level == 0 {
+			delete(t.prefix, name)
+		} else {
+			delete(t.assoc, name)
+		}
+	})
+	return true
+}
+
+// RegisterOperator - add the binary, prefix or postfix operator or override the existing one of the same fixity.
+// A prefix operator shares the level 0 with functions and unary operators, like '-'
+func (r *FunctionRegistry) RegisterOperator(op Operator) error {
+	if err := op.Validate(); err != nil {
+		return err
+	}
+	r.update(func(t *functionTable) {
+		switch op.Fixity {
+		case Infix:
+			// a binary operator has a single level
+			for level := LevelPower; level < LevelsOfPriorities; level++ {
+				delete(t.levels[level], op.Name)
+			}
+			t.levels[op.Level][op.Name] = entry{fn: op.Func, signature: op.Signature}
+			delete(t.assoc, op.Name)
+			if op.Associativity != LeftAssoc {
+				t.assoc[op.Name] = op.Associativity
+			}
+		case Prefix:
+			d := Descriptor{Name: op.Name, Func: op.Func, MinArgs: 1, MaxArgs: 1, Signature: op.Signature}
+			t.levels[0][op.Name] = entry{fn: op.Func, signature: op.Signature, descriptor: &d}
+			t.prefix[op.Name] = op.Level
+		case Postfix:
+			t.postfix[op.Name] = op
+		}
+	})
+	return nil
+}
+
+// Operator - return the descriptor of the operator. Operators registered by Register are described too:
+// a binary one by its level and associativity, a unary one from the level 0 has DefaultPrefixLevel
+func (r *FunctionRegistry) Operator(fixity Fixity, name string) (Operator, bool) {
+	t := r.load()
+	switch fixity {
+	case Infix:
+		for level := LevelPower; level < LevelsOfPriorities; level++ {
+			if e, ok := t.levels[level][name]; ok {
+				return Operator{Name: name, Fixity: Infix, Level: level, Associativity: t.assoc[name], Func: e.fn, Signature: e.signature}, true
+			}
+		}
+	case Prefix:
+		if e, ok := t.levels[0][name]; ok {
+			level, ok := t.prefix[name]
+			if !ok {
+				level = DefaultPrefixLevel
+			}
+			return Operator{Name: name, Fixity: Prefix, Level: level, Func: e.fn, Signature: e.signature}, true
+		}
+	case Postfix:
+		op, ok := t.postfix[name]
+		return op, ok
+	}
+	return Operator{}, false
+}
+
+// LookupPostfix - return the function of the postfix operator
+func (r *FunctionRegistry) LookupPostfix(name string) (FuncType, bool) {
+	op, ok := r.load().postfix[name]
+	return op.Func, ok
+}
+
+// PostfixSignature - the types of the postfix operator, it has NumericSignature if its signature is not declared
+func (r *FunctionRegistry) PostfixSignature(name string) (Signature, bool) {
+	op, ok := r.load().postfix[name]
+	if !ok {
+		return Signature{}, false
+	}
+	if op.Signature != nil {
+		return *op.Signature, true
+	}
+	return NumericSignature, true
+}
+
+// RemoveOperator - delete the operator of the fixity, return false if it was not registered
+func (r *FunctionRegistry) RemoveOperator(fixity Fixity, name string) bool {
+	op, ok := r.Operator(fixity, name)
+	if !ok {
+		return false
+	}
+	switch fixity {
+	case Infix:
+		return r.Remove(op.Level, name)
+	case Prefix:
+		return r.Remove(0, name)
+	}
+	r.update(func(t *functionTable) {
+		delete(t.postfix, name)
+	})
+	return true
+}
+
+// PostfixNames - sorted names of the postfix operators
+func (r *FunctionRegistry) PostfixNames() []string {
+	t := r.load()
+	names := make([]string, 0, len(t.postfix))
+	for name := range t.postfix {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetAssociativity - change the grouping of the binary operator, it is kept when the operator is registered again.
+// Binary operators are left-associative by default
+func (r *FunctionRegistry) SetAssociativity(name string, a Associativity) {
+	r.update(func(t *functionTable) {
+		if a == LeftAssoc {
+			delete(t.assoc, name)
+		} else {
+			t.assoc[name] = a
+		}
+	})
+}
+
+// Associativity - return the grouping of the binary operator
+func (r *FunctionRegistry) Associativity(name string) Associativity {
+	return r.load().assoc[name]
+}
+
+// Names - sorted names of the functions registered on the priority level
+func (r *FunctionRegistry) Names(level int) []string {
+	if level < 0 || level >= LevelsOfPriorities {
+		return nil
+	}
+	t := r.load()
+	names := make([]string, 0, len(t.levels[level]))
+	for name := range t.levels[level] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkLevel - checks that the priority level exists
+func checkLevel(level int) error {
+	if level < 0 || level >= LevelsOfPriorities {
+		return errors.New("incorrect priority level: " + strconv.Itoa(level) + ". Need: 0.." + strconv.Itoa(LevelsOfPriorities-1))
+	}
+	return nil
+}
+
+// load - return the current table, the zero registry has an empty one
+func (r *FunctionRegistry) load() *functionTable {
+	if t := r.table.Load(); t != nil {
+		return t
+	}
+	return &functionTable{}
+}
+
+// update - apply the change to a copy of the table and publish it
+func (r *FunctionRegistry) update(change func(t *functionTable)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.load().copy()
+	change(&t)
+	r.table.Store(&t)
+}
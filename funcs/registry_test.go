@@ -0,0 +1,345 @@
+package funcs_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+func one(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return decimal.NewFromInt(1), nil
+}
+
+func two(args ...decimal.Decimal) (decimal.Decimal, error) {
+	return decimal.NewFromInt(2), nil
+}
+
+func call(t *testing.T, r *funcs.FunctionRegistry, level int, name string) decimal.Decimal {
+	f, ok := r.Lookup(level, name)
+	if !ok {
+		t.Fatal("function '" + name + "' not found")
+	}
+	res, _ := f()
+	return res
+}
+
+func TestFunctionRegistry(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{
+		{"f": one},
+		{"*": one},
+		{},
+	})
+
+	if _, ok := r.Lookup(1, "f"); ok {
+		t.Error("function found on the wrong level")
+	}
+	if _, ok := r.Lookup(funcs.LevelsOfPriorities, "f"); ok {
+		t.Error("function found on the incorrect level")
+	}
+
+	r.Register(0, "g", two)
+	if !call(t, r, 0, "g").Equal(decimal.NewFromInt(2)) {
+		t.Error("incorrect registered function")
+	}
+
+	// override
+	r.Register(0, "f", two)
+	if !call(t, r, 0, "f").Equal(decimal.NewFromInt(2)) {
+		t.Error("function was not overridden")
+	}
+
+	if !r.Remove(0, "g") {
+		t.Error("function was not removed")
+	}
+	if r.Remove(0, "g") {
+		t.Error("removed function was found")
+	}
+
+	names := r.Names(0)
+	if len(names) != 1 || names[0] != "f" {
+		t.Error("incorrect names: ", names)
+	}
+
+	// an unknown level is an error instead of a panic
+	value := func(s funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+		return funcs.Null, nil
+	}
+	lazyValue := func(s funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+		return funcs.Null, nil
+	}
+	for _, level := range []int{-1, funcs.LevelsOfPriorities} {
+		for i, err := range []error{
+			r.Register(level, "h", one),
+			r.RegisterLazy(level, "h", func(args ...funcs.LazyArg) (decimal.Decimal, error) { return decimal.Zero, nil }),
+			r.RegisterWithSettings(level, "h", func(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) { return decimal.Zero, nil }),
+			r.RegisterValue(level, "h", value),
+			r.RegisterLazyValue(level, "h", lazyValue),
+		} {
+			if err == nil || err.Error() != "incorrect priority level: "+strconv.Itoa(level)+". Need: 0.."+strconv.Itoa(funcs.LevelsOfPriorities-1) {
+				t.Error("incorrect error of registration "+strconv.Itoa(i)+" on level "+strconv.Itoa(level)+": ", err)
+			}
+		}
+	}
+	if names := r.Names(0); len(names) != 1 {
+		t.Error("function was registered on the incorrect level: ", names)
+	}
+
+	var zero funcs.FunctionRegistry
+	if _, ok := zero.Lookup(0, "f"); ok {
+		t.Error("function found in the empty registry")
+	}
+	zero.Register(2, "+", one)
+	if _, ok := zero.Lookup(2, "+"); !ok {
+		t.Error("function not found in the zero registry")
+	}
+}
+
+func TestFunctionRegistryClone(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{{"f": one}, {}, {}})
+	c := r.Clone()
+
+	c.Register(0, "f", two)
+	c.Register(0, "g", two)
+	r.Remove(0, "f")
+
+	if _, ok := r.Lookup(0, "g"); ok {
+		t.Error("change of the clone affects the source")
+	}
+	if !call(t, c, 0, "f").Equal(decimal.NewFromInt(2)) {
+		t.Error("change of the source affects the clone")
+	}
+}
+
+func TestFunctionRegistryConcurrent(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{{"f": one}, {}, {}})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				name := "f" + strconv.Itoa(g) + "_" + strconv.Itoa(i)
+				r.Register(0, name, two)
+				if _, ok := r.Lookup(0, "f"); !ok {
+					t.Error("function not found")
+				}
+				c := r.Clone()
+				if _, ok := c.Lookup(0, name); !ok {
+					t.Error("function not found in the clone")
+				}
+				r.Remove(0, name)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if len(r.Names(0)) != 1 {
+		t.Error("incorrect count of functions: ", r.Names(0))
+	}
+}
+
+func TestFunctionRegistryDescriptors(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{{"plain": one}, {}, {}})
+
+	err := r.RegisterFunction(funcs.Descriptor{Name: "g", Func: two, MinArgs: 1, MaxArgs: 2, Description: "g(x[, y])", Pure: true})
+	if err != nil {
+		t.Error(err)
+	}
+	if err := r.RegisterFunction(funcs.Descriptor{Name: "bad"}); err == nil {
+		t.Error("incorrect descriptor was registered")
+	}
+
+	d, ok := r.Describe("g")
+	if !ok || d.Description != "g(x[, y])" || d.MaxArgs != 2 || !d.Pure {
+		t.Error("incorrect descriptor: ", d)
+	}
+	if !call(t, r, 0, "g").Equal(decimal.NewFromInt(2)) {
+		t.Error("incorrect registered function")
+	}
+
+	d, ok = r.Describe("plain")
+	if !ok || !d.Variadic || d.Name != "plain" {
+		t.Error("incorrect descriptor of the function without metadata: ", d)
+	}
+
+	// registration without metadata drops the old descriptor
+	r.Register(0, "g", one)
+	if d, _ := r.Describe("g"); !d.Variadic {
+		t.Error("descriptor was not replaced")
+	}
+
+	r.Remove(0, "g")
+	if _, ok := r.Describe("g"); ok {
+		t.Error("removed function was described")
+	}
+
+	list := r.Descriptors()
+	if len(list) != 1 || list[0].Name != "plain" {
+		t.Error("incorrect list of descriptors: ", list)
+	}
+}
+
+func TestFunctionRegistryLazy(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{{}, {}, {}})
+	first := func(args ...funcs.LazyArg) (decimal.Decimal, error) {
+		return args[0].Evaluate()
+	}
+
+	r.RegisterLazy(2, "??", first)
+	if _, ok := r.LookupLazy(2, "??"); !ok {
+		t.Error("lazy operator not found")
+	}
+	// eager adapter
+	f, ok := r.Lookup(2, "??")
+	if !ok {
+		t.Fatal("eager version of the lazy operator not found")
+	}
+	if res, _ := f(decimal.NewFromInt(3), decimal.NewFromInt(4)); !res.Equal(decimal.NewFromInt(3)) {
+		t.Error("incorrect result of the eager version: " + res.String())
+	}
+
+	r.Register(2, "??", one)
+	if _, ok := r.LookupLazy(2, "??"); ok {
+		t.Error("lazy operator was not overridden")
+	}
+
+	if err := r.RegisterFunction(funcs.Descriptor{Name: "first", Lazy: first, MinArgs: 1, Variadic: true}); err != nil {
+		t.Error(err)
+	}
+	if _, ok := r.LookupLazy(0, "first"); !ok {
+		t.Error("lazy function not found")
+	}
+	if err := r.RegisterFunction(funcs.Descriptor{Name: "first", Func: one, Lazy: first}); err == nil {
+		t.Error("descriptor with Func and Lazy was registered")
+	}
+	r.Remove(0, "first")
+	if _, ok := r.LookupLazy(0, "first"); ok {
+		t.Error("removed lazy function was found")
+	}
+}
+
+func TestFunctionRegistryAssociativity(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{{}, {"^": one}})
+	if r.Associativity("^") != funcs.LeftAssoc {
+		t.Error("binary operator is not left-associative by default")
+	}
+	c := r.Clone()
+	r.SetAssociativity("^", funcs.RightAssoc)
+	if r.Associativity("^") != funcs.RightAssoc {
+		t.Error("associativity was not changed")
+	}
+	if c.Associativity("^") != funcs.LeftAssoc {
+		t.Error("associativity of the clone was changed")
+	}
+
+	// the associativity belongs to the operator, not to its function
+	r.Register(funcs.LevelPower, "^", two)
+	if r.Associativity("^") != funcs.RightAssoc {
+		t.Error("associativity was dropped by the registration")
+	}
+	r.Remove(funcs.LevelPower, "^")
+	if r.Associativity("^") != funcs.LeftAssoc {
+		t.Error("associativity of the removed operator was kept")
+	}
+}
+
+func TestFunctionRegistryOperators(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{
+		{"-": one},
+		{},
+		{"*": one},
+	})
+
+	op, ok := r.Operator(funcs.Infix, "*")
+	if !ok || op.Level != funcs.LevelMultiplicative || op.Associativity != funcs.LeftAssoc {
+		t.Error("incorrect descriptor of the binary operator: ", op)
+	}
+	op, ok = r.Operator(funcs.Prefix, "-")
+	if !ok || op.Level != funcs.DefaultPrefixLevel {
+		t.Error("incorrect descriptor of the prefix operator: ", op)
+	}
+	if _, ok := r.Operator(funcs.Postfix, "-"); ok {
+		t.Error("prefix operator was described as postfix")
+	}
+
+	// a binary operator is moved to the new level
+	if err := r.RegisterOperator(funcs.Operator{Name: "*", Level: funcs.LevelPower, Associativity: funcs.RightAssoc, Func: two}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Lookup(funcs.LevelMultiplicative, "*"); ok {
+		t.Error("binary operator was kept on the old level")
+	}
+	if op, _ := r.Operator(funcs.Infix, "*"); op.Level != funcs.LevelPower || op.Associativity != funcs.RightAssoc {
+		t.Error("incorrect descriptor of the moved operator: ", op)
+	}
+
+	if err := r.RegisterOperator(funcs.Operator{Name: "-", Fixity: funcs.Prefix, Level: funcs.LevelAdditive, Func: two}); err != nil {
+		t.Fatal(err)
+	}
+	if op, _ := r.Operator(funcs.Prefix, "-"); op.Level != funcs.LevelAdditive || !call(t, r, 0, "-").Equal(decimal.NewFromInt(2)) {
+		t.Error("incorrect prefix operator: ", op)
+	}
+	if d, _ := r.Describe("-"); d.Variadic || d.MinArgs != 1 || d.MaxArgs != 1 {
+		t.Error("incorrect descriptor of the prefix operator: ", d)
+	}
+
+	if err := r.RegisterOperator(funcs.Operator{Name: "!", Fixity: funcs.Postfix, Func: two}); err != nil {
+		t.Fatal(err)
+	}
+	if f, ok := r.LookupPostfix("!"); !ok || f == nil {
+		t.Error("postfix operator not found")
+	}
+	if sig, ok := r.PostfixSignature("!"); !ok || sig.Param(0) != funcs.KindNumber || sig.Result != funcs.KindNumber {
+		t.Error("incorrect default signature of the postfix operator: ", sig)
+	}
+	boolSig := funcs.Signature{Params: []funcs.Kind{funcs.KindNumber}, Result: funcs.KindBool}
+	if err := r.RegisterOperator(funcs.Operator{Name: "!!", Fixity: funcs.Postfix, Func: one, Signature: &boolSig}); err != nil {
+		t.Fatal(err)
+	}
+	if sig, ok := r.PostfixSignature("!!"); !ok || sig.Result != funcs.KindBool {
+		t.Error("incorrect signature of the postfix operator: ", sig)
+	}
+	r.RemoveOperator(funcs.Postfix, "!!")
+	if _, ok := r.PostfixSignature("-"); ok {
+		t.Error("signature of unknown postfix operator was found")
+	}
+	c := r.Clone()
+	if !r.RemoveOperator(funcs.Postfix, "!") {
+		t.Error("postfix operator was not removed")
+	}
+	if len(r.PostfixNames()) != 0 || len(c.PostfixNames()) != 1 {
+		t.Error("incorrect postfix operators after the removal: ", r.PostfixNames(), c.PostfixNames())
+	}
+	if !r.RemoveOperator(funcs.Prefix, "-") {
+		t.Error("prefix operator was not removed")
+	}
+	if _, ok := r.Operator(funcs.Prefix, "-"); ok {
+		t.Error("removed prefix operator was found")
+	}
+
+	for _, op := range []funcs.Operator{
+		{Name: "", Func: one},
+		{Name: "mod", Level: funcs.LevelMultiplicative, Func: one},
+		{Name: "1x", Level: funcs.LevelMultiplicative, Func: one},
+		{Name: "a b", Level: funcs.LevelMultiplicative, Func: one},
+		// the symbols of scripts and lambdas
+		{Name: "=", Level: funcs.LevelComparison, Func: one},
+		{Name: "max=", Level: funcs.LevelComparison, Func: one},
+		{Name: ";", Level: funcs.LevelMultiplicative, Func: one},
+		{Name: "->", Level: funcs.LevelMultiplicative, Func: one},
+		{Name: "->>", Fixity: funcs.Postfix, Level: funcs.LevelUnary, Func: one},
+		{Name: "//", Level: funcs.LevelMultiplicative},
+		{Name: "//", Level: funcs.LevelUnary, Func: one},
+		{Name: "//", Fixity: funcs.Postfix, Level: -1, Func: one},
+		{Name: "//", Fixity: funcs.Fixity(3), Func: one},
+		{Name: "//", Level: funcs.LevelMultiplicative, Associativity: funcs.Associativity(2), Func: one},
+	} {
+		if err := op.Validate(); err == nil {
+			t.Error("incorrect operator is valid: ", op.Name)
+		}
+	}
+}
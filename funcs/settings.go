@@ -0,0 +1,153 @@
+package funcs
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode - the rule which is used to drop digits beyond the precision
+type RoundingMode int
+
+const (
+	// RoundHalfUp - round to the nearest, a half is rounded away from zero: 2.5 -> 3, -2.5 -> -3
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven - round to the nearest, a half is rounded to the even digit (banker's rounding): 2.5 -> 2, 3.5 -> 4
+	RoundHalfEven
+	// RoundDown - round towards zero: 2.9 -> 2, -2.9 -> -2
+	RoundDown
+	// RoundCeiling - round towards +infinity: 2.1 -> 3, -2.9 -> -2
+	RoundCeiling
+	// RoundFloor - round towards -infinity: 2.9 -> 2, -2.1 -> -3
+	RoundFloor
+)
+
+var roundingModeNames = map[RoundingMode]string{
+	RoundHalfUp:   "half-up",
+	RoundHalfEven: "half-even",
+	RoundDown:     "down",
+	RoundCeiling:  "ceiling",
+	RoundFloor:    "floor",
+}
+
+func (m RoundingMode) String() string {
+	if name, ok := roundingModeNames[m]; ok {
+		return name
+	}
+	return "RoundingMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// ParseRoundingMode - return the rounding mode by its name, like "half-even"
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	for m, s := range roundingModeNames {
+		if s == name {
+			return m, nil
+		}
+	}
+	return RoundHalfUp, errors.New("unknown rounding mode: '" + name + "'")
+}
+
+// AngleMode - the unit of angles of trigonometric functions
+type AngleMode int
+
+const (
+	Radians AngleMode = iota
+	Degrees
+)
+
+var angleModeNames = map[AngleMode]string{
+	Radians: "radians",
+	Degrees: "degrees",
+}
+
+func (m AngleMode) String() string {
+	if name, ok := angleModeNames[m]; ok {
+		return name
+	}
+	return "AngleMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// ParseAngleMode - return the angle mode by its name, "radians" or "degrees"
+func ParseAngleMode(name string) (AngleMode, error) {
+	for m, s := range angleModeNames {
+		if s == name {
+			return m, nil
+		}
+	}
+	return Radians, errors.New("unknown angle mode: '" + name + "'")
+}
+
+// Settings - the arithmetic settings of an evaluation.
+// Results which can't be represented exactly, like 1/3 or sqrt(2),
+// are rounded to Precision digits after the decimal point by the Rounding rule.
+// Trigonometric functions take and return angles in the Angle units
+type Settings struct {
+	Precision int32
+	Rounding  RoundingMode
+	Angle     AngleMode
+}
+
+// MaxPrecision - the greatest Precision of Settings, inexact functions get slower with more digits
+const MaxPrecision = 1000
+
+// DefaultSettings - 16 digits rounded half up, the same as decimal.Div with the default decimal.DivisionPrecision,
+// angles in radians
+var DefaultSettings = Settings{Precision: 16, Rounding: RoundHalfUp, Angle: Radians}
+
+// Validate - checks that the settings can be used for evaluation
+func (s Settings) Validate() error {
+	if s.Precision < 0 || s.Precision > MaxPrecision {
+		return errors.New("incorrect precision: " + strconv.Itoa(int(s.Precision)) + ", it must be from 0 to " + strconv.Itoa(MaxPrecision))
+	}
+	if _, ok := roundingModeNames[s.Rounding]; !ok {
+		return errors.New("unknown rounding mode: " + s.Rounding.String())
+	}
+	if _, ok := angleModeNames[s.Angle]; !ok {
+		return errors.New("unknown angle mode: " + s.Angle.String())
+	}
+	return nil
+}
+
+// Round - round d to the precision by the rounding mode
+func (s Settings) Round(d decimal.Decimal) decimal.Decimal {
+	switch s.Rounding {
+	case RoundHalfEven:
+		return d.RoundBank(s.Precision)
+	case RoundDown:
+		return d.RoundDown(s.Precision)
+	case RoundCeiling:
+		return d.RoundCeil(s.Precision)
+	case RoundFloor:
+		return d.RoundFloor(s.Precision)
+	}
+	return d.Round(s.Precision)
+}
+
+// Div - the quotient a / b rounded by the settings. It is correctly rounded for any mode:
+// the quotient is truncated to one more digit and a non-zero remainder is kept as a tiny sticky term,
+// so exact halves and inexact results are distinguished
+func (s Settings) Div(a, b decimal.Decimal) (decimal.Decimal, error) {
+	if b.IsZero() {
+		return decimal.Zero, errors.New("division by zero")
+	}
+	q, r := a.QuoRem(b, s.Precision+1)
+	if !r.IsZero() {
+		sticky := decimal.New(1, -(s.Precision + 2))
+		if a.Sign() != b.Sign() {
+			sticky = sticky.Neg()
+		}
+		q = q.Add(sticky)
+	}
+	return s.Round(q), nil
+}
+
+// SettingsFuncType - type of functions which depend on the arithmetic settings of the evaluation
+type SettingsFuncType func(s Settings, args ...decimal.Decimal) (decimal.Decimal, error)
+
+// Bind - the function which always uses the settings
+func (f SettingsFuncType) Bind(s Settings) FuncType {
+	return func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return f(s, args...)
+	}
+}
@@ -0,0 +1,170 @@
+package funcs_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+func TestSettingsDiv(t *testing.T) {
+	type TestData struct {
+		a, b      int64
+		precision int32
+		mode      funcs.RoundingMode
+		res       string
+	}
+
+	data := []TestData{
+		{1, 3, 4, funcs.RoundHalfUp, "0.3333"},
+		{2, 3, 4, funcs.RoundHalfUp, "0.6667"},
+		{-2, 3, 4, funcs.RoundHalfUp, "-0.6667"},
+		{1, 8, 2, funcs.RoundHalfUp, "0.13"},
+		{-1, 8, 2, funcs.RoundHalfUp, "-0.13"},
+
+		{1, 8, 2, funcs.RoundHalfEven, "0.12"},
+		{3, 8, 2, funcs.RoundHalfEven, "0.38"},
+		{-1, 8, 2, funcs.RoundHalfEven, "-0.12"},
+		{2, 3, 4, funcs.RoundHalfEven, "0.6667"},
+		// 0.125000...1 is not a half, the sticky digit keeps it
+		{1000001, 8000000, 2, funcs.RoundHalfEven, "0.13"},
+
+		{2, 3, 4, funcs.RoundDown, "0.6666"},
+		{-2, 3, 4, funcs.RoundDown, "-0.6666"},
+
+		{1, 3, 4, funcs.RoundCeiling, "0.3334"},
+		{-1, 3, 4, funcs.RoundCeiling, "-0.3333"},
+		{1, 4, 4, funcs.RoundCeiling, "0.25"},
+
+		{1, 3, 4, funcs.RoundFloor, "0.3333"},
+		{-1, 3, 4, funcs.RoundFloor, "-0.3334"},
+		{1, -3, 4, funcs.RoundFloor, "-0.3334"},
+		{-1, -3, 4, funcs.RoundFloor, "0.3333"},
+		{-1, 4, 4, funcs.RoundFloor, "-0.25"},
+
+		{1000, 3, -1, funcs.RoundHalfUp, "330"},
+		{10, 4, 0, funcs.RoundHalfEven, "2"},
+	}
+
+	for _, d := range data {
+		s := funcs.Settings{Precision: d.precision, Rounding: d.mode}
+		res, err := s.Div(decimal.NewFromInt(d.a), decimal.NewFromInt(d.b))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect " + d.mode.String() + " division, need: " + d.res + ", but get: " + res.String())
+		}
+	}
+
+	if _, err := funcs.DefaultSettings.Div(decimal.NewFromInt(1), decimal.Zero); err == nil {
+		t.Error("incorrect division by zero handling")
+	}
+
+	// the default settings are the same as decimal.Div
+	res, _ := funcs.DefaultSettings.Div(decimal.NewFromInt(2), decimal.NewFromInt(3))
+	if !res.Equal(decimal.NewFromInt(2).Div(decimal.NewFromInt(3))) {
+		t.Error("incorrect default division: " + res.String())
+	}
+}
+
+func TestSettingsRound(t *testing.T) {
+	type TestData struct {
+		mode funcs.RoundingMode
+		res  []string
+	}
+
+	values := []string{"2.5", "3.5", "-2.5", "2.1", "-2.1"}
+	data := []TestData{
+		{funcs.RoundHalfUp, []string{"3", "4", "-3", "2", "-2"}},
+		{funcs.RoundHalfEven, []string{"2", "4", "-2", "2", "-2"}},
+		{funcs.RoundDown, []string{"2", "3", "-2", "2", "-2"}},
+		{funcs.RoundCeiling, []string{"3", "4", "-2", "3", "-2"}},
+		{funcs.RoundFloor, []string{"2", "3", "-3", "2", "-3"}},
+	}
+
+	for _, d := range data {
+		s := funcs.Settings{Rounding: d.mode}
+		for i, v := range values {
+			res := s.Round(decimal.RequireFromString(v))
+			if !res.Equal(decimal.RequireFromString(d.res[i])) {
+				t.Error("incorrect " + d.mode.String() + " rounding of " + v + ": " + res.String())
+			}
+		}
+
+		mode, err := funcs.ParseRoundingMode(d.mode.String())
+		if err != nil || mode != d.mode {
+			t.Error("incorrect rounding mode parsing: " + d.mode.String())
+		}
+	}
+
+	if _, err := funcs.ParseRoundingMode("up"); err == nil {
+		t.Error("incorrect unknown rounding mode handling")
+	}
+	if err := (funcs.Settings{Rounding: funcs.RoundingMode(100)}).Validate(); err == nil {
+		t.Error("incorrect settings validation")
+	}
+	if err := (funcs.Settings{Precision: funcs.MaxPrecision}).Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, precision := range []int32{-1, funcs.MaxPrecision + 1} {
+		if err := (funcs.Settings{Precision: precision}).Validate(); err == nil {
+			t.Error("incorrect precision was accepted: ", precision)
+		}
+	}
+}
+
+func TestFunctionRegistrySettings(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{})
+	precision := func(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.NewFromInt32(s.Precision), nil
+	}
+	r.RegisterWithSettings(1, "/", precision)
+
+	// without settings the function uses the default ones
+	if !call(t, r, 1, "/").Equal(decimal.NewFromInt32(funcs.DefaultSettings.Precision)) {
+		t.Error("incorrect function with default settings")
+	}
+
+	f, ok := r.LookupWithSettings(1, "/", funcs.Settings{Precision: 2})
+	if !ok {
+		t.Fatal("function with settings not found")
+	}
+	if res, _ := f(); !res.Equal(decimal.NewFromInt(2)) {
+		t.Error("incorrect function with settings: " + res.String())
+	}
+
+	// a plain function ignores the settings
+	r.Register(1, "/", one)
+	f, _ = r.LookupWithSettings(1, "/", funcs.Settings{Precision: 2})
+	if res, _ := f(); !res.Equal(decimal.NewFromInt(1)) {
+		t.Error("incorrect overridden function: " + res.String())
+	}
+
+	if err := r.RegisterFunction(funcs.Descriptor{Name: "p", WithSettings: precision, MinArgs: 0, MaxArgs: 0}); err != nil {
+		t.Fatal(err)
+	}
+	f, _ = r.LookupWithSettings(0, "p", funcs.Settings{Precision: 3})
+	if res, _ := f(); !res.Equal(decimal.NewFromInt(3)) {
+		t.Error("incorrect described function with settings: " + res.String())
+	}
+	if err := r.RegisterFunction(funcs.Descriptor{Name: "p", Func: one, WithSettings: precision}); err == nil {
+		t.Error("incorrect validation of the descriptor with two functions")
+	}
+}
+
+func TestAngleMode(t *testing.T) {
+	for _, m := range []funcs.AngleMode{funcs.Radians, funcs.Degrees} {
+		mode, err := funcs.ParseAngleMode(m.String())
+		if err != nil || mode != m {
+			t.Error("incorrect angle mode parsing: " + m.String())
+		}
+	}
+	if _, err := funcs.ParseAngleMode("grads"); err == nil {
+		t.Error("incorrect unknown angle mode handling")
+	}
+	if err := (funcs.Settings{Angle: funcs.AngleMode(2)}).Validate(); err == nil {
+		t.Error("incorrect settings validation")
+	}
+}
@@ -0,0 +1,80 @@
+package funcs
+
+import "strconv"
+
+// Signature - the types of the arguments and of the result of a function, they are used by the type checker.
+// KindAny accepts any type
+type Signature struct {
+	Params []Kind // the last one is repeated for the rest of arguments, no params accept any arguments
+	Result Kind
+	// Infer - optional, the result type by the name of the function and the types of the arguments.
+	// It is called after the check of Params and returns a *TypeError if the arguments don't match each other
+	Infer func(name string, args []Kind) (Kind, error)
+	// NumericItems - the items of list arguments must be numbers or bools, like the items of sum(xs).
+	// The type checker knows the types of the items of list literals only
+	NumericItems bool
+}
+
+var (
+	// NumericSignature - the signature of functions over numbers, a bool argument is converted to 1 or 0
+	NumericSignature = Signature{Params: []Kind{KindNumber}, Result: KindNumber}
+
+	// AnySignature - the signature of functions over typed values without a declared signature
+	AnySignature = Signature{Params: []Kind{KindAny}, Result: KindAny}
+)
+
+// Param - the type of the argument i
+func (s Signature) Param(i int) Kind {
+	switch {
+	case len(s.Params) == 0:
+		return KindAny
+	case i < len(s.Params):
+		return s.Params[i]
+	}
+	return s.Params[len(s.Params)-1]
+}
+
+// Apply - the result type of the call with the argument types, name is used in errors.
+// All mismatched arguments are reported
+func (s Signature) Apply(name string, args []Kind) (Kind, []error) {
+	var errs []error
+	for i, arg := range args {
+		if need := s.Param(i); !arg.AssignableTo(need) {
+			errs = append(errs, &TypeError{What: "argument " + strconv.Itoa(i+1) + " of '" + name + "'", Need: need, Get: arg})
+		}
+	}
+	if len(errs) > 0 {
+		return s.Result, errs
+	}
+	if s.Infer != nil {
+		res, err := s.Infer(name, args)
+		if err != nil {
+			return res, []error{err}
+		}
+		return res, nil
+	}
+	return s.Result, nil
+}
+
+// AssignableTo - checks that a value of the type can be passed where need is expected.
+// Numbers and bools are converted to each other, KindAny matches any type
+func (k Kind) AssignableTo(need Kind) bool {
+	switch {
+	case k == need || k == KindAny || need == KindAny:
+		return true
+	case need == KindNumber || need == KindBool:
+		return k == KindNumber || k == KindBool
+	}
+	return false
+}
+
+// CommonKind - the type of a value which is a or b, null is a missing value of the other type
+func CommonKind(a, b Kind) Kind {
+	switch {
+	case a == b || b == KindNull:
+		return a
+	case a == KindNull:
+		return b
+	}
+	return KindAny
+}
@@ -0,0 +1,104 @@
+package funcs_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+)
+
+func TestSignature(t *testing.T) {
+	sig := funcs.Signature{Params: []funcs.Kind{funcs.KindString, funcs.KindNumber}, Result: funcs.KindBool}
+	if sig.Param(0) != funcs.KindString || sig.Param(5) != funcs.KindNumber || (funcs.Signature{}).Param(0) != funcs.KindAny {
+		t.Error("incorrect types of params")
+	}
+
+	res, errs := sig.Apply("f", []funcs.Kind{funcs.KindString, funcs.KindBool, funcs.KindAny})
+	if res != funcs.KindBool || len(errs) != 0 {
+		t.Error("incorrect result: ", res, errs)
+	}
+	_, errs = sig.Apply("f", []funcs.Kind{funcs.KindNumber, funcs.KindNull, funcs.KindString})
+	if len(errs) != 3 || errs[0].Error() != "incorrect type of argument 1 of 'f'. Need: string, but get: number" ||
+		errs[2].Error() != "incorrect type of argument 3 of 'f'. Need: number, but get: string" {
+		t.Error("incorrect errors: ", errs)
+	}
+
+	sig.Infer = func(name string, args []funcs.Kind) (funcs.Kind, error) {
+		return args[1], nil
+	}
+	if res, _ := sig.Apply("f", []funcs.Kind{funcs.KindString, funcs.KindBool}); res != funcs.KindBool {
+		t.Error("incorrect inferred result: ", res)
+	}
+}
+
+func TestKindAssignable(t *testing.T) {
+	type TestData struct {
+		kind funcs.Kind
+		need funcs.Kind
+		ok   bool
+	}
+
+	data := []TestData{
+		{funcs.KindNumber, funcs.KindNumber, true},
+		{funcs.KindBool, funcs.KindNumber, true},
+		{funcs.KindNumber, funcs.KindBool, true},
+		{funcs.KindAny, funcs.KindString, true},
+		{funcs.KindNull, funcs.KindAny, true},
+		{funcs.KindNull, funcs.KindNumber, false},
+		{funcs.KindString, funcs.KindNumber, false},
+		{funcs.KindNumber, funcs.KindString, false},
+	}
+
+	for _, d := range data {
+		if d.kind.AssignableTo(d.need) != d.ok {
+			t.Error("incorrect check of " + d.kind.String() + " for " + d.need.String())
+		}
+	}
+	if funcs.CommonKind(funcs.KindNull, funcs.KindString) != funcs.KindString ||
+		funcs.CommonKind(funcs.KindNumber, funcs.KindString) != funcs.KindAny {
+		t.Error("incorrect common kind")
+	}
+}
+
+func TestFunctionRegistrySignatures(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{{"one": one}, {}, {}})
+	if sig, ok := r.Signature(0, "one"); !ok || sig.Result != funcs.KindNumber {
+		t.Error("incorrect signature of the numeric function: ", sig)
+	}
+	if _, ok := r.Signature(0, "two"); ok {
+		t.Error("signature of the missing function was found")
+	}
+	if r.SetSignature(0, "two", funcs.AnySignature) {
+		t.Error("signature of the missing function was set")
+	}
+
+	r.RegisterValue(2, "++", func(s funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+		return funcs.Null, nil
+	})
+	if sig, _ := r.Signature(2, "++"); sig.Result != funcs.KindAny {
+		t.Error("incorrect signature of the function over values: ", sig)
+	}
+	if !r.SetSignature(2, "++", funcs.Signature{Params: []funcs.Kind{funcs.KindString}, Result: funcs.KindString}) {
+		t.Error("signature was not set")
+	}
+	if sig, _ := r.Signature(2, "++"); sig.Result != funcs.KindString {
+		t.Error("signature was not declared: ", sig)
+	}
+	if sig, _ := r.Clone().Signature(2, "++"); sig.Result != funcs.KindString {
+		t.Error("signature was not cloned: ", sig)
+	}
+	// the signature is dropped with the old version of the function
+	r.Register(2, "++", two)
+	if sig, _ := r.Signature(2, "++"); sig.Result != funcs.KindNumber {
+		t.Error("signature of the old function was kept: ", sig)
+	}
+
+	err := r.RegisterFunction(funcs.Descriptor{Name: "upper", Value: func(s funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+		return args[0], nil
+	}, MinArgs: 1, MaxArgs: 1, Signature: &funcs.Signature{Params: []funcs.Kind{funcs.KindString}, Result: funcs.KindString}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig, _ := r.Signature(0, "upper"); sig.Param(0) != funcs.KindString {
+		t.Error("signature of the descriptor was not registered: ", sig)
+	}
+}
@@ -0,0 +1,295 @@
+// Package trig - trigonometric and hyperbolic functions computed to the precision of the parser settings.
+// The numbers pi and e are the built-in constants of the parser, not functions.
+// They are not registered by default, call Register to add them to a parser:
+//
+//	trig.Register(parser.Functions())
+//
+// Angles are measured in the units of funcs.Settings.Angle, radians by default
+package trig
+
+import (
+	"errors"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/funcs/decmath"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	one        = decimal.NewFromInt(1)
+	degrees90  = decimal.NewFromInt(90)
+	degrees180 = decimal.NewFromInt(180)
+	degrees360 = decimal.NewFromInt(360)
+
+	// Functions - metadata of the trigonometric functions
+	Functions = []funcs.Descriptor{
+		{Name: "sin", WithSettings: Sin, MinArgs: 1, MaxArgs: 1, Description: "sin(x) - sine of the angle x", Pure: true},
+		{Name: "cos", WithSettings: Cos, MinArgs: 1, MaxArgs: 1, Description: "cos(x) - cosine of the angle x", Pure: true},
+		{Name: "tan", WithSettings: Tan, MinArgs: 1, MaxArgs: 1, Description: "tan(x) - tangent of the angle x", Pure: true},
+		{Name: "asin", WithSettings: Asin, MinArgs: 1, MaxArgs: 1, Description: "asin(x) - the angle which sine is x", Pure: true},
+		{Name: "acos", WithSettings: Acos, MinArgs: 1, MaxArgs: 1, Description: "acos(x) - the angle which cosine is x", Pure: true},
+		{Name: "atan", WithSettings: Atan, MinArgs: 1, MaxArgs: 1, Description: "atan(x) - the angle which tangent is x", Pure: true},
+		{Name: "atan2", WithSettings: Atan2, MinArgs: 2, MaxArgs: 2, Description: "atan2(y, x) - the angle between the x axis and the point (x, y)", Pure: true},
+		{Name: "sinh", WithSettings: Sinh, MinArgs: 1, MaxArgs: 1, Description: "sinh(x) - hyperbolic sine of x", Pure: true},
+		{Name: "cosh", WithSettings: Cosh, MinArgs: 1, MaxArgs: 1, Description: "cosh(x) - hyperbolic cosine of x", Pure: true},
+		{Name: "tanh", WithSettings: Tanh, MinArgs: 1, MaxArgs: 1, Description: "tanh(x) - hyperbolic tangent of x", Pure: true},
+	}
+)
+
+// Register - add the trigonometric functions to the registry, existing functions with the same names are overridden
+func Register(r *funcs.FunctionRegistry) error {
+	for _, d := range Functions {
+		if err := r.RegisterFunction(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sin - sine of the angle
+func Sin(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'sin' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	if s.Angle == funcs.Degrees {
+		if res, ok := sinDegrees(args[0]); ok {
+			return res, nil
+		}
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Sin(toRadians(s, args[0], precision), precision)
+	})
+}
+
+// Cos - cosine of the angle
+func Cos(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'cos' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	if s.Angle == funcs.Degrees {
+		// cos(x) = sin(x + 90)
+		if res, ok := sinDegrees(args[0].Add(degrees90)); ok {
+			return res, nil
+		}
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Cos(toRadians(s, args[0], precision), precision)
+	})
+}
+
+// Tan - tangent of the angle
+func Tan(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'tan' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	if s.Angle == funcs.Degrees {
+		if res, ok, err := tanDegrees(args[0]); ok {
+			return res, err
+		}
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Tan(toRadians(s, args[0], precision), precision)
+	})
+}
+
+// Asin - the angle which sine is x, in [-90, 90] degrees
+func Asin(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'asin' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	if s.Angle == funcs.Degrees {
+		if res, ok := asinDegrees[args[0].String()]; ok {
+			return decimal.NewFromInt(res), nil
+		}
+	}
+	return angle(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Asin(args[0], precision)
+	})
+}
+
+// Acos - the angle which cosine is x, in [0, 180] degrees
+func Acos(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'acos' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	if s.Angle == funcs.Degrees {
+		// acos(x) = 90 - asin(x)
+		if res, ok := asinDegrees[args[0].String()]; ok {
+			return decimal.NewFromInt(90 - res), nil
+		}
+	}
+	return angle(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Acos(args[0], precision)
+	})
+}
+
+// Atan - the angle which tangent is x, in (-90, 90) degrees
+func Atan(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'atan' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	if s.Angle == funcs.Degrees {
+		if res, ok := atan2Degrees(args[0], one); ok {
+			return res, nil
+		}
+	}
+	return angle(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Atan(args[0], precision)
+	})
+}
+
+// Atan2 - atan2(y, x) is the angle between the x axis and the point (x, y), in (-180, 180] degrees
+func Atan2(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'atan2' function", 2, args); err != nil {
+		return decimal.Zero, err
+	}
+	if s.Angle == funcs.Degrees {
+		if res, ok := atan2Degrees(args[0], args[1]); ok {
+			return res, nil
+		}
+	}
+	return angle(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Atan2(args[0], args[1], precision)
+	})
+}
+
+// Sinh - hyperbolic sine of x
+func Sinh(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'sinh' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Sinh(args[0], precision)
+	})
+}
+
+// Cosh - hyperbolic cosine of x
+func Cosh(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'cosh' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Cosh(args[0], precision)
+	})
+}
+
+// Tanh - hyperbolic tangent of x
+func Tanh(s funcs.Settings, args ...decimal.Decimal) (decimal.Decimal, error) {
+	if err := funcs.CheckArgsCount("'tanh' function", 1, args); err != nil {
+		return decimal.Zero, err
+	}
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		return decmath.Tanh(args[0], precision)
+	})
+}
+
+// rounded - calculate the result with guard digits and round it by the settings
+func rounded(s funcs.Settings, f func(precision int32) (decimal.Decimal, error)) (decimal.Decimal, error) {
+	res, err := f(s.Precision + decmath.GuardDigits)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return s.Round(res), nil
+}
+
+// angle - calculate the angle in radians and convert it to the units of the settings
+func angle(s funcs.Settings, f func(precision int32) (decimal.Decimal, error)) (decimal.Decimal, error) {
+	return rounded(s, func(precision int32) (decimal.Decimal, error) {
+		res, err := f(precision + 3)
+		if err != nil || s.Angle != funcs.Degrees {
+			return res, err
+		}
+		return res.Mul(degrees180).DivRound(decmath.Pi(precision+3), precision), nil
+	})
+}
+
+// toRadians - convert the angle in the units of the settings to radians
+func toRadians(s funcs.Settings, x decimal.Decimal, precision int32) decimal.Decimal {
+	if s.Angle != funcs.Degrees {
+		return x
+	}
+	// the full turns are dropped exactly, so the error doesn't depend on x
+	return x.Mod(degrees360).Mul(decmath.Pi(precision+3)).DivRound(degrees180, precision+3)
+}
+
+// reduceDegrees - the angle reduced to [0, 360)
+func reduceDegrees(x decimal.Decimal) decimal.Decimal {
+	x = x.Mod(degrees360)
+	if x.IsNegative() {
+		x = x.Add(degrees360)
+	}
+	return x
+}
+
+// exact values of sine for angles in degrees
+var sinDegreesTable = map[int64]decimal.Decimal{
+	0:   decimal.Zero,
+	30:  decimal.New(5, -1),
+	90:  one,
+	150: decimal.New(5, -1),
+	180: decimal.Zero,
+	210: decimal.New(-5, -1),
+	270: one.Neg(),
+	330: decimal.New(-5, -1),
+}
+
+// sinDegrees - the exact sine of the angle in degrees, if it is rational
+func sinDegrees(x decimal.Decimal) (decimal.Decimal, bool) {
+	x = reduceDegrees(x)
+	if !x.IsInteger() {
+		return decimal.Zero, false
+	}
+	res, ok := sinDegreesTable[x.IntPart()]
+	return res, ok
+}
+
+// tanDegrees - the exact tangent of the angle in degrees, if it is rational or undefined
+func tanDegrees(x decimal.Decimal) (decimal.Decimal, bool, error) {
+	r := reduceDegrees(x).Mod(degrees180)
+	if !r.IsInteger() {
+		return decimal.Zero, false, nil
+	}
+	switch r.IntPart() {
+	case 0:
+		return decimal.Zero, true, nil
+	case 45:
+		return one, true, nil
+	case 90:
+		return decimal.Zero, true, errors.New("tangent is undefined for " + x.String() + " degrees")
+	case 135:
+		return one.Neg(), true, nil
+	}
+	return decimal.Zero, false, nil
+}
+
+// exact arcsine in degrees
+var asinDegrees = map[string]int64{
+	"-1":   -90,
+	"-0.5": -30,
+	"0":    0,
+	"0.5":  30,
+	"1":    90,
+}
+
+// atan2Degrees - the exact angle in degrees for the points on the axes and on the diagonals
+func atan2Degrees(y, x decimal.Decimal) (decimal.Decimal, bool) {
+	var res int64
+	switch {
+	case y.IsZero() && x.IsNegative():
+		res = 180
+	case y.IsZero():
+		res = 0
+	case x.IsZero():
+		res = 90
+	case y.Abs().Equal(x.Abs()) && x.IsPositive():
+		res = 45
+	case y.Abs().Equal(x.Abs()):
+		res = 135
+	default:
+		return decimal.Zero, false
+	}
+	if y.IsNegative() {
+		res = -res
+	}
+	return decimal.NewFromInt(res), true
+}
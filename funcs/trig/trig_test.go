@@ -0,0 +1,155 @@
+package trig_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/funcs/trig"
+	"github.com/arconomy/go-math-expression-parser/parser"
+	"github.com/shopspring/decimal"
+)
+
+type TestData struct {
+	exp string
+	res string
+}
+
+func check(t *testing.T, p *parser.Parser, data []TestData) {
+	for _, d := range data {
+		exp, err := p.Parse(d.exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := exp.Evaluate(nil)
+		if err != nil {
+			t.Error(d.exp + ": " + err.Error())
+		} else if !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect result of " + d.exp + ", need: " + d.res + ", but get: " + res.String())
+		}
+	}
+}
+
+func newParser(t *testing.T) *parser.Parser {
+	p := parser.NewParser()
+	if err := trig.Register(p.Functions()); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestRadians(t *testing.T) {
+	check(t, newParser(t), []TestData{
+		{"pi", "3.1415926535897932"},
+		{"e", "2.7182818284590452"},
+		{"sin(0)", "0"},
+		{"sin(1)", "0.8414709848078965"},
+		{"sin(pi)", "0"},
+		{"sin(pi / 2)", "1"},
+		{"cos(1)", "0.5403023058681397"},
+		{"cos(pi)", "-1"},
+		{"tan(1)", "1.5574077246549022"},
+		{"asin(1)", "1.5707963267948966"},
+		{"acos(-1)", "3.1415926535897932"},
+		{"atan(1) * 4", "3.1415926535897932"},
+		{"atan2(-1, -1)", "-2.3561944901923449"},
+		{"sinh(1)", "1.1752011936438015"},
+		{"cosh(1)", "1.5430806348152438"},
+		{"tanh(1)", "0.7615941559557649"},
+	})
+}
+
+func TestDegrees(t *testing.T) {
+	p := newParser(t)
+	if err := p.SetAngleMode(funcs.Degrees); err != nil {
+		t.Fatal(err)
+	}
+	check(t, p, []TestData{
+		{"sin(30)", "0.5"},
+		{"sin(-30)", "-0.5"},
+		{"sin(390)", "0.5"},
+		{"sin(180)", "0"},
+		{"sin(45)", "0.7071067811865475"},
+		{"sin(1)", "0.0174524064372835"},
+		{"cos(60)", "0.5"},
+		{"cos(90)", "0"},
+		{"cos(-180)", "-1"},
+		{"tan(45)", "1"},
+		{"tan(-45)", "-1"},
+		{"tan(60)", "1.7320508075688773"},
+		{"asin(0.5)", "30"},
+		{"asin(-1)", "-90"},
+		{"asin(0.1)", "5.7391704772667863"},
+		{"acos(0.5)", "60"},
+		{"acos(-1)", "180"},
+		{"acos(0.1)", "84.2608295227332137"},
+		{"atan(1)", "45"},
+		{"atan(2)", "63.4349488229220106"},
+		{"atan2(1, -1)", "135"},
+		{"atan2(0, -5)", "180"},
+		{"atan2(-3, 0)", "-90"},
+		{"sinh(1)", "1.1752011936438015"},
+	})
+
+	exp, err := p.Parse("tan(90)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exp.Evaluate(nil); err == nil {
+		t.Error("incorrect tangent of 90 degrees handling")
+	}
+
+	// the same expression in radians
+	exp, err = p.Parse("sin(x)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]decimal.Decimal{"x": decimal.NewFromInt(30)}
+	res, err := exp.EvaluateWithSettings(vars, funcs.Settings{Precision: 4, Angle: funcs.Radians})
+	if err != nil || !res.Equal(decimal.RequireFromString("-0.988")) {
+		t.Error("incorrect result in radians: " + res.String())
+	}
+}
+
+func TestTrigErrors(t *testing.T) {
+	p := newParser(t)
+	for _, s := range []string{"asin(2)", "acos(-1.5)", "sin(1e2000)", "tan(-1e100000)"} {
+		exp, err := p.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := exp.Evaluate(nil); err == nil {
+			t.Error("incorrect error handling: " + s)
+		}
+	}
+	for _, s := range []string{"sin()", "atan2(1)", "pi(1)"} {
+		if _, err := p.Parse(s); err == nil {
+			t.Error("incorrect count of args was not handled: " + s)
+		}
+	}
+	if err := p.SetAngleMode(funcs.AngleMode(5)); err == nil {
+		t.Error("incorrect angle mode was accepted")
+	}
+}
+
+func TestAngleModeAndSettings(t *testing.T) {
+	p := newParser(t)
+	if err := p.SetAngleMode(funcs.Degrees); err != nil {
+		t.Fatal(err)
+	}
+	// the precision and the rounding mode are changed, the angle mode is kept
+	if err := p.SetSettings(funcs.Settings{Precision: 50, Angle: funcs.Radians}); err != nil {
+		t.Fatal(err)
+	}
+	if s := p.Settings(); s.Angle != funcs.Degrees || s.Precision != 50 {
+		t.Error("incorrect settings: ", s)
+	}
+	check(t, p, []TestData{{"sin(30)", "0.5"}})
+
+	// the angle mode is changed, the precision is kept
+	if err := p.SetAngleMode(funcs.Radians); err != nil {
+		t.Fatal(err)
+	}
+	if s := p.Settings(); s.Angle != funcs.Radians || s.Precision != 50 {
+		t.Error("incorrect settings: ", s)
+	}
+}
@@ -1,7 +1,11 @@
 package userfunc
 
 import (
+	"errors"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
 	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/arconomy/go-math-expression-parser/internal"
 	"github.com/shopspring/decimal"
 )
 
@@ -17,27 +21,40 @@ func (f *Func) GetVarList(vars map[string]interface{}) {
 	}
 }
 
-// Evaluate function
-func (f *Func) Evaluate(vars map[string]decimal.Decimal, p interfaces.ExpParser) (decimal.Decimal, error) {
-	var args []decimal.Decimal
+// Evaluate function. Arguments of a lazy function are passed unevaluated
+func (f *Func) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return internal.EvaluateNumber(f, vars, p)
+}
+
+// EvaluateValue - evaluate function over typed values. Arguments of a lazy function are passed unevaluated
+func (f *Func) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	if lazy, ok := p.Functions().LookupLazyValue(0, f.Op, p.Settings()); ok {
+		return lazy(internal.LazyArgs(f.Args, vars, p)...)
+	}
+	fn, ok := p.Functions().LookupValue(0, f.Op, p.Settings())
+	if !ok {
+		return funcs.Null, errors.New("function '" + f.Op + "' is not supported")
+	}
+	var args []funcs.Value
 	for _, arg := range f.Args {
-		res, err := arg.Evaluate(vars, p)
+		res, err := arg.EvaluateValue(vars, p)
 		if err != nil {
-			return decimal.Zero, err
+			return funcs.Null, err
 		}
 		args = append(args, res)
 	}
-	res, err := p.GetFunctions()[0][f.Op](args...)
-	return res, err
+	return fn(args...)
 }
 
 // toString conversation
 func (f *Func) String() string {
 	str := ""
-	for _, arg := range f.Args {
-		str += arg.String() + ","
+	for i, arg := range f.Args {
+		if i > 0 {
+			str += ","
+		}
+		str += arg.String()
 	}
-	str = str[:len(str)-1]
 	return "( " + string(f.Op) + " ( " + str + " ) )"
 }
 
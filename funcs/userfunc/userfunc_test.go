@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/arconomy/go-math-expression-parser/funcs"
 	"github.com/arconomy/go-math-expression-parser/funcs/userfunc"
 	"github.com/arconomy/go-math-expression-parser/interfaces"
 	"github.com/arconomy/go-math-expression-parser/internal"
@@ -257,11 +258,12 @@ func TestUserFunction(t *testing.T) {
 	}
 
 	for _, d := range data {
-		_, err := pars.Parse(d.input)
+		exp, err := pars.Parse(d.input)
 		if err != nil {
 			t.Error(err)
+			continue
 		}
-		res, err := pars.Evaluate(d.vars)
+		res, err := exp.Evaluate(d.vars)
 		if err != nil {
 			t.Error(err)
 		}
@@ -270,3 +272,52 @@ func TestUserFunction(t *testing.T) {
 		}
 	}
 }
+
+func TestLazyFunction(t *testing.T) {
+	// try(a, b) - a, or b if a can't be evaluated
+	try := func(args ...funcs.LazyArg) (decimal.Decimal, error) {
+		if res, err := args[0].Evaluate(); err == nil {
+			return res, nil
+		}
+		return args[1].Evaluate()
+	}
+	// vars(a) - count of variables used in a, the argument is not evaluated
+	varsCount := func(args ...funcs.LazyArg) (decimal.Decimal, error) {
+		exp := args[0].(interface{ Expression() interfaces.Expression }).Expression()
+		vars := map[string]interface{}{}
+		exp.GetVarList(vars)
+		return decimal.NewFromInt(int64(len(vars))), nil
+	}
+
+	pars := parser.NewParser()
+	if err := pars.RegisterFunction(funcs.Descriptor{Name: "try", Lazy: try, MinArgs: 2, MaxArgs: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pars.RegisterFunction(funcs.Descriptor{Name: "vars", Lazy: varsCount, MinArgs: 1, MaxArgs: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	type TestData struct {
+		input  string
+		output decimal.Decimal
+	}
+	data := []TestData{
+		{"try(1 / x, -1)", decimal.NewFromInt(-1)},
+		{"try(unknown, x + 1)", decimal.NewFromInt(1)},
+		{"vars(a * b + sqrt(c / a))", decimal.NewFromInt(3)},
+	}
+	for _, d := range data {
+		exp, err := pars.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		res, err := exp.Evaluate(map[string]decimal.Decimal{"x": decimal.Zero})
+		if err != nil {
+			t.Error(err)
+		}
+		if !res.Equal(d.output) {
+			t.Error("incorrect result, need: " + d.output.String() + ", but get: " + res.String())
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package funcs
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Kind - the type of a Value
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindNumber
+	KindBool
+	KindString
+	KindList
+	KindFunc
+	KindObject
+	KindAny // the type of a value which is known at evaluation time only, it is used by signatures
+)
+
+var kindNames = [...]string{
+	KindNull:   "null",
+	KindNumber: "number",
+	KindBool:   "bool",
+	KindString: "string",
+	KindList:   "list",
+	KindFunc:   "function",
+	KindObject: "object",
+	KindAny:    "any",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kindNames[k]
+}
+
+// Value - the typed value of an expression: a number (integers are numbers too), a bool, a string, a list,
+// a function, an object with members or null. The zero Value is null
+type Value struct {
+	kind Kind
+	num  decimal.Decimal
+	str  string
+	b    bool
+	list []Value
+	fn   Callable
+	obj  Object
+}
+
+// Callable - the function value, like the closure of the lambda 'x -> x * 2'
+type Callable interface {
+	// Call - call the function with the arguments
+	Call(args ...Value) (Value, error)
+	String() string
+}
+
+// Null - the missing value
+var Null = Value{}
+
+// NumberValue - the number value
+func NumberValue(d decimal.Decimal) Value {
+	return Value{kind: KindNumber, num: d}
+}
+
+// BoolValue - the bool value
+func BoolValue(b bool) Value {
+	return Value{kind: KindBool, b: b}
+}
+
+// StringValue - the string value
+func StringValue(s string) Value {
+	return Value{kind: KindString, str: s}
+}
+
+// ListValue - the list of the values, the slice is not copied and must not be changed after the call
+func ListValue(items ...Value) Value {
+	if items == nil {
+		items = []Value{}
+	}
+	return Value{kind: KindList, list: items}
+}
+
+// NumberList - the list of the numbers, like monthly revenues
+func NumberList(nums ...decimal.Decimal) Value {
+	return ListValue(NumberValues(nums)...)
+}
+
+// FuncValue - the function value
+func FuncValue(f Callable) Value {
+	return Value{kind: KindFunc, fn: f}
+}
+
+// Kind - the type of the value
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+// IsNull - checks that the value is missing
+func (v Value) IsNull() bool {
+	return v.kind == KindNull
+}
+
+// AsNumber - the number, a bool is converted to True or False. Other types are a *TypeError
+func (v Value) AsNumber() (decimal.Decimal, error) {
+	switch v.kind {
+	case KindNumber:
+		return v.num, nil
+	case KindBool:
+		return Bool(v.b), nil
+	}
+	return decimal.Zero, &TypeError{What: "value", Need: KindNumber, Get: v.kind}
+}
+
+// AsBool - the bool, a number is true if it is not zero. Other types are a *TypeError
+func (v Value) AsBool() (bool, error) {
+	switch v.kind {
+	case KindBool:
+		return v.b, nil
+	case KindNumber:
+		return IsTrue(v.num), nil
+	}
+	return false, &TypeError{What: "value", Need: KindBool, Get: v.kind}
+}
+
+// AsString - the string, other types are a *TypeError
+func (v Value) AsString() (string, error) {
+	if v.kind != KindString {
+		return "", &TypeError{What: "value", Need: KindString, Get: v.kind}
+	}
+	return v.str, nil
+}
+
+// AsList - the items of the list, other types are a *TypeError. The result must not be changed
+func (v Value) AsList() ([]Value, error) {
+	if v.kind != KindList {
+		return nil, &TypeError{What: "value", Need: KindList, Get: v.kind}
+	}
+	return v.list, nil
+}
+
+// AsFunc - the function, other types are a *TypeError
+func (v Value) AsFunc() (Callable, error) {
+	if v.kind != KindFunc {
+		return nil, &TypeError{What: "value", Need: KindFunc, Get: v.kind}
+	}
+	return v.fn, nil
+}
+
+// Equal - checks that the values have the same type and are equal, lists are compared by items
+// and functions and objects are equal to themselves only
+func (v Value) Equal(o Value) bool {
+	if v.kind != o.kind {
+		return false
+	}
+	switch v.kind {
+	case KindNumber:
+		return v.num.Equal(o.num)
+	case KindBool:
+		return v.b == o.b
+	case KindString:
+		return v.str == o.str
+	case KindFunc:
+		return v.fn == o.fn
+	case KindObject:
+		return sameObject(v.obj, o.obj)
+	case KindList:
+		if len(v.list) != len(o.list) {
+			return false
+		}
+		for i, item := range v.list {
+			if !item.Equal(o.list[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String - the value in the syntax of expressions, a string is quoted
+func (v Value) String() string {
+	switch v.kind {
+	case KindNumber:
+		return v.num.String()
+	case KindBool:
+		return strconv.FormatBool(v.b)
+	case KindString:
+		return strconv.Quote(v.str)
+	case KindList:
+		strs := make([]string, len(v.list))
+		for i, item := range v.list {
+			strs[i] = item.String()
+		}
+		return "[" + strings.Join(strs, ", ") + "]"
+	case KindFunc:
+		return v.fn.String()
+	case KindObject:
+		return objectString(v.obj)
+	}
+	return "null"
+}
+
+// NumberValues - convert the numbers to values
+func NumberValues(args []decimal.Decimal) []Value {
+	res := make([]Value, len(args))
+	for i, arg := range args {
+		res[i] = NumberValue(arg)
+	}
+	return res
+}
+
+// NumberArgs - convert the arguments of the function or operator what to numbers,
+// a bool is converted to True or False. Other types are a *TypeError
+func NumberArgs(what string, args []Value) ([]decimal.Decimal, error) {
+	nums := make([]decimal.Decimal, len(args))
+	for i, arg := range args {
+		num, err := arg.AsNumber()
+		if err != nil {
+			return nil, DescribeTypeError(err, "argument "+strconv.Itoa(i+1)+" of '"+what+"'")
+		}
+		nums[i] = num
+	}
+	return nums, nil
+}
+
+// TypeError - the value has an unexpected type
+type TypeError struct {
+	What string // the description of the value, like "argument 1 of 'sqrt'"
+	Need Kind
+	Get  Kind
+}
+
+func (e *TypeError) Error() string {
+	return "incorrect type of " + e.What + ". Need: " + e.Need.String() + ", but get: " + e.Get.String()
+}
+
+// DescribeTypeError - set the description of the value to the *TypeError, other errors are returned as is
+func DescribeTypeError(err error, what string) error {
+	if te, ok := err.(*TypeError); ok {
+		return &TypeError{What: what, Need: te.Need, Get: te.Get}
+	}
+	return err
+}
+
+// ValueFuncType - type of functions over typed values, they get the arithmetic settings of the evaluation
+type ValueFuncType func(s Settings, args ...Value) (Value, error)
+
+// Bind - the function which always uses the settings
+func (f ValueFuncType) Bind(s Settings) BoundValueFunc {
+	return func(args ...Value) (Value, error) {
+		return f(s, args...)
+	}
+}
+
+// Numeric - adapter to call the function with numbers, a bool result is converted to True or False
+func (f ValueFuncType) Numeric(s Settings) FuncType {
+	return func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		res, err := f(s, NumberValues(args)...)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		num, err := res.AsNumber()
+		return num, DescribeTypeError(err, "the result")
+	}
+}
+
+// BoundValueFunc - the function over typed values bound to the settings
+type BoundValueFunc func(args ...Value) (Value, error)
+
+// LazyValueArg - an unevaluated argument of a lazy function over typed values
+type LazyValueArg interface {
+	LazyArg
+	// Value - evaluate the argument in the scope of the call
+	Value() (Value, error)
+}
+
+// LazyValueFuncType - type of functions over typed values which receive unevaluated arguments
+type LazyValueFuncType func(s Settings, args ...LazyValueArg) (Value, error)
+
+// Bind - the function which always uses the settings
+func (f LazyValueFuncType) Bind(s Settings) BoundLazyValueFunc {
+	return func(args ...LazyValueArg) (Value, error) {
+		return f(s, args...)
+	}
+}
+
+// Numeric - adapter to call the lazy function with numbers, a bool result is converted to True or False
+func (f LazyValueFuncType) Numeric(s Settings) LazyFuncType {
+	return func(args ...LazyArg) (decimal.Decimal, error) {
+		valueArgs := make([]LazyValueArg, len(args))
+		for i, arg := range args {
+			if va, ok := arg.(LazyValueArg); ok {
+				valueArgs[i] = va
+			} else {
+				valueArgs[i] = numericArg{arg}
+			}
+		}
+		res, err := f(s, valueArgs...)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		num, err := res.AsNumber()
+		return num, DescribeTypeError(err, "the result")
+	}
+}
+
+// BoundLazyValueFunc - the lazy function over typed values bound to the settings
+type BoundLazyValueFunc func(args ...LazyValueArg) (Value, error)
+
+// numericArg - the lazy argument which is a number
+type numericArg struct {
+	LazyArg
+}
+
+func (a numericArg) Value() (Value, error) {
+	num, err := a.Evaluate()
+	if err != nil {
+		return Null, err
+	}
+	return NumberValue(num), nil
+}
@@ -0,0 +1,159 @@
+package funcs_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+func TestValue(t *testing.T) {
+	type TestData struct {
+		val  funcs.Value
+		kind funcs.Kind
+		str  string
+	}
+
+	data := []TestData{
+		{funcs.Null, funcs.KindNull, "null"},
+		{funcs.Value{}, funcs.KindNull, "null"},
+		{funcs.NumberValue(decimal.RequireFromString("1.50")), funcs.KindNumber, "1.5"},
+		{funcs.BoolValue(true), funcs.KindBool, "true"},
+		{funcs.StringValue("a \"b\""), funcs.KindString, `"a \"b\""`},
+		{funcs.ListValue(funcs.NumberValue(decimal.NewFromInt(1)), funcs.StringValue("a"), funcs.ListValue()), funcs.KindList, `[1, "a", []]`},
+	}
+
+	for _, d := range data {
+		if d.val.Kind() != d.kind {
+			t.Error("incorrect kind of " + d.str + ": " + d.val.Kind().String())
+		}
+		if d.val.String() != d.str {
+			t.Error("incorrect string: " + d.val.String() + ", need: " + d.str)
+		}
+		if d.val.IsNull() != (d.kind == funcs.KindNull) {
+			t.Error("incorrect IsNull of " + d.str)
+		}
+		if !d.val.Equal(d.val) {
+			t.Error("value is not equal to itself: " + d.str)
+		}
+	}
+	if funcs.NumberValue(decimal.NewFromInt(1)).Equal(funcs.BoolValue(true)) {
+		t.Error("values of different types are equal")
+	}
+	if funcs.NumberList(decimal.NewFromInt(1)).Equal(funcs.NumberList(decimal.NewFromInt(1), decimal.Zero)) ||
+		!funcs.NumberList(decimal.NewFromInt(1)).Equal(funcs.ListValue(funcs.NumberValue(decimal.RequireFromString("1.0")))) {
+		t.Error("incorrect comparison of lists")
+	}
+}
+
+func TestValueConversion(t *testing.T) {
+	if num, err := funcs.BoolValue(true).AsNumber(); err != nil || !num.Equal(funcs.True) {
+		t.Error("incorrect number of true: ", num, err)
+	}
+	if b, err := funcs.NumberValue(decimal.NewFromFloat(-0.5)).AsBool(); err != nil || !b {
+		t.Error("incorrect bool of -0.5: ", b, err)
+	}
+	if s, err := funcs.StringValue("USD").AsString(); err != nil || s != "USD" {
+		t.Error("incorrect string: ", s, err)
+	}
+
+	_, err := funcs.StringValue("USD").AsNumber()
+	if err == nil || err.Error() != "incorrect type of value. Need: number, but get: string" {
+		t.Error("incorrect error: ", err)
+	}
+	if _, err := funcs.Null.AsBool(); err == nil {
+		t.Error("null was converted to bool")
+	}
+	if _, err := funcs.NumberValue(decimal.Zero).AsString(); err == nil {
+		t.Error("number was converted to string")
+	}
+	if items, err := funcs.NumberList(decimal.Zero).AsList(); err != nil || len(items) != 1 {
+		t.Error("incorrect items of the list: ", items, err)
+	}
+	if _, err := funcs.NumberList().AsNumber(); err == nil || err.Error() != "incorrect type of value. Need: number, but get: list" {
+		t.Error("incorrect error: ", err)
+	}
+
+	_, err = funcs.NumberArgs("sqrt", []funcs.Value{funcs.NumberValue(decimal.Zero), funcs.Null})
+	if te, ok := err.(*funcs.TypeError); !ok || te.Need != funcs.KindNumber || te.Get != funcs.KindNull ||
+		err.Error() != "incorrect type of argument 2 of 'sqrt'. Need: number, but get: null" {
+		t.Error("incorrect error: ", err)
+	}
+}
+
+func TestFunctionRegistryValues(t *testing.T) {
+	r := funcs.NewFunctionRegistry([funcs.LevelsOfPriorities]map[string]funcs.FuncType{{}, {}, {}})
+	r.Register(2, "*", func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return args[0].Mul(args[1]), nil
+	})
+	concat := func(s funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+		str := ""
+		for _, arg := range args {
+			s, err := arg.AsString()
+			if err != nil {
+				return funcs.Null, err
+			}
+			str += s
+		}
+		return funcs.StringValue(str), nil
+	}
+	r.RegisterValue(2, "++", concat)
+
+	f, ok := r.LookupValue(2, "++", funcs.DefaultSettings)
+	if !ok {
+		t.Fatal("function over values not found")
+	}
+	if res, _ := f(funcs.StringValue("a"), funcs.StringValue("b")); !res.Equal(funcs.StringValue("ab")) {
+		t.Error("incorrect result: " + res.String())
+	}
+	// the numeric version passes numbers, which are not strings
+	if _, err := call2(r, 2, "++"); err == nil || err.Error() != "incorrect type of value. Need: string, but get: number" {
+		t.Error("incorrect error of the numeric version: ", err)
+	}
+
+	// numeric functions are adapted
+	mul, ok := r.LookupValue(2, "*", funcs.DefaultSettings)
+	if !ok {
+		t.Fatal("numeric function not found")
+	}
+	if res, _ := mul(funcs.NumberValue(decimal.NewFromInt(3)), funcs.BoolValue(true)); !res.Equal(funcs.NumberValue(decimal.NewFromInt(3))) {
+		t.Error("incorrect result: " + res.String())
+	}
+	_, err := mul(funcs.NumberValue(decimal.NewFromInt(3)), funcs.StringValue("x"))
+	if err == nil || err.Error() != "incorrect type of argument 2 of '*'. Need: number, but get: string" {
+		t.Error("incorrect error: ", err)
+	}
+
+	r.Register(2, "++", one)
+	if _, ok := r.LookupValue(2, "++", funcs.DefaultSettings); !ok {
+		t.Error("numeric function not found")
+	}
+	if res, _ := call2(r, 2, "++"); !res.Equal(decimal.NewFromInt(1)) {
+		t.Error("function over values was not overridden")
+	}
+
+	first := func(s funcs.Settings, args ...funcs.LazyValueArg) (funcs.Value, error) {
+		return args[0].Value()
+	}
+	if err := r.RegisterFunction(funcs.Descriptor{Name: "first", LazyValue: first, MinArgs: 1, Variadic: true}); err != nil {
+		t.Error(err)
+	}
+	if _, ok := r.LookupLazyValue(0, "first", funcs.DefaultSettings); !ok {
+		t.Error("lazy function over values not found")
+	}
+	if _, ok := r.LookupLazy(0, "first"); !ok {
+		t.Error("numeric version of the lazy function not found")
+	}
+	if err := r.RegisterFunction(funcs.Descriptor{Name: "first", Value: concat, LazyValue: first}); err == nil {
+		t.Error("descriptor with Value and LazyValue was registered")
+	}
+	r.Remove(0, "first")
+	if _, ok := r.LookupLazyValue(0, "first", funcs.DefaultSettings); ok {
+		t.Error("removed lazy function was found")
+	}
+}
+
+func call2(r *funcs.FunctionRegistry, level int, name string) (decimal.Decimal, error) {
+	f, _ := r.Lookup(level, name)
+	return f(decimal.NewFromInt(1), decimal.NewFromInt(2))
+}
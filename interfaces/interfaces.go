@@ -5,19 +5,30 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-type ExpParser interface {
-	AddFunction(f funcs.FuncType, s string)
-	GetFunctions() [funcs.LevelsOfPriorities]map[string]funcs.FuncType
-	String() string
-	Parse(str string) (Expression, error)
-	Evaluate(vars map[string]decimal.Decimal) (decimal.Decimal, error)
+// Context - the read-only set of functions and the arithmetic settings used to evaluate an expression
+type Context interface {
+	Functions() *funcs.FunctionRegistry
+	Settings() funcs.Settings
+}
+
+// Scope - the variables of an evaluation, a scope is not changed while the expression is evaluated
+type Scope interface {
+	// Var - the value of the variable or of the path of a member, like "order.total", ok is false if it is not set
+	Var(name string) (val funcs.Value, ok bool)
+}
+
+// VarLister - the object which can report variables used by it
+type VarLister interface {
+	GetVarList(vars map[string]interface{})
 }
 
 // Exp - the base interface for Term and Node structures
 type Expression interface {
+	VarLister
 	String() string
-	Evaluate(vars map[string]decimal.Decimal, p ExpParser) (decimal.Decimal, error)
-	GetVarList(vars map[string]interface{})
+	Evaluate(vars map[string]decimal.Decimal, p Context) (decimal.Decimal, error)
+	// EvaluateValue - evaluate over typed values, Evaluate is EvaluateValue with numbers
+	EvaluateValue(vars Scope, p Context) (funcs.Value, error)
 }
 
 // Function - the struct which contains a function and an argument
@@ -1,7 +1,6 @@
 package internal_test
 
 import (
-	"strconv"
 	"testing"
 
 	"github.com/arconomy/go-math-expression-parser/internal"
@@ -33,11 +32,12 @@ func TestEvalWithVars(t *testing.T) {
 	pars := expp.NewParser()
 
 	for _, d := range data {
-		_, err := pars.Parse(d.input)
+		exp, err := pars.Parse(d.input)
 		if err != nil {
 			t.Error(err)
+			continue
 		}
-		res, err := pars.Evaluate(d.vars)
+		res, err := exp.Evaluate(d.vars)
 		if err != nil {
 			t.Error(err)
 		}
@@ -47,33 +47,6 @@ func TestEvalWithVars(t *testing.T) {
 	}
 }
 
-func TestParenthesisIsCorrect(t *testing.T) {
-	type TestData struct {
-		s       string
-		correct bool
-	}
-
-	data := []TestData{
-		{"", true},
-		{"()", true},
-		{")(", false},
-		{"func2(600, 60, 6)", true},
-		{"(func2(600, 60, 6))", true},
-		{"func2(func2(700,70,7), 222, -8)", true},
-		{"func2(func2(700,70,7), 222, -8", false},
-		{"func2(func2(700,70,7), 222, -8))", false},
-		{"(func2(func2(700,70,7), 222, -8)", false},
-	}
-	for i, d := range data {
-		if _, cor := internal.ParenthesisIsCorrect(d.s); cor != data[i].correct {
-			t.Error("incorrect result for " + strconv.Itoa(i) + " case: '" + d.s +
-				"'. Need: " + strconv.FormatBool(data[i].correct) +
-				", but get: " + strconv.FormatBool(cor))
-		}
-	}
-
-}
-
 func Foo1(args ...decimal.Decimal) (decimal.Decimal, error) {
 	return decimal.NewFromFloat(0.1), nil
 }
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// Lambda - the anonymous function 'x -> x * 2' or '(acc, x) -> acc + x', its value is a Closure
+type Lambda struct {
+	Params []string
+	Body   interfaces.Expression
+}
+
+// GetVarList - report the variables of the body except the parameters
+func (l *Lambda) GetVarList(vars map[string]interface{}) {
+	used := make(map[string]interface{})
+	l.Body.GetVarList(used)
+	params := make(map[string]struct{}, len(l.Params))
+	for _, param := range l.Params {
+		params[param] = struct{}{}
+	}
+	for name := range used {
+		if _, ok := params[VarRoot(name)]; !ok {
+			vars[name] = struct{}{}
+		}
+	}
+}
+
+// Evaluate - a function is not a number, so it is always an error
+func (l *Lambda) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(l, vars, p)
+}
+
+// EvaluateValue - return the function which captures the variables of the scope
+func (l *Lambda) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	// a scope is not changed, a script makes a new one for its next assignment
+	return funcs.FuncValue(&Closure{Lambda: l, Vars: vars, Ctx: p}), nil
+}
+
+// toString conversation
+func (l *Lambda) String() string {
+	return "( -> ( " + strings.Join(l.Params, ",") + " ) " + l.Body.String() + " )"
+}
+
+// Closure - the lambda with the captured variables of its scope
+type Closure struct {
+	Lambda *Lambda
+	Vars   interfaces.Scope
+	Ctx    interfaces.Context
+}
+
+// Call - evaluate the body, the parameters shadow the captured variables
+func (c *Closure) Call(args ...funcs.Value) (funcs.Value, error) {
+	if err := funcs.CheckArgsCount("function '"+c.String()+"'", len(c.Lambda.Params), args); err != nil {
+		return funcs.Null, err
+	}
+	params := make(Vars, len(args))
+	for i, param := range c.Lambda.Params {
+		params[param] = args[i]
+	}
+	return c.Lambda.Body.EvaluateValue(&LocalScope{Parent: c.Vars, Vars: params}, c.Ctx)
+}
+
+func (c *Closure) String() string {
+	return c.Lambda.String()
+}
@@ -0,0 +1,42 @@
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/arconomy/go-math-expression-parser/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestLambda(t *testing.T) {
+	// (acc, x) -> acc + x * k
+	lambda := &internal.Lambda{Params: []string{"acc", "x"}, Body: &internal.Node{Op: "+", LExp: &internal.Term{Val: "acc"},
+		RExp: &internal.Node{Op: "*", LExp: &internal.Term{Val: "x"}, RExp: &internal.Term{Val: "k"}}}}
+	if lambda.String() != "( -> ( acc,x ) ( + acc ( * x k ) ) )" {
+		t.Error("incorrect string conversion = " + lambda.String())
+	}
+	if v := parser.GetVarList(lambda); !reflect.DeepEqual(v, []string{"k"}) {
+		t.Error("incorrect variables: ", v)
+	}
+
+	p := parser.NewParser()
+	vars := internal.Vars{"k": funcs.NumberValue(decimal.NewFromInt(2)), "x": funcs.StringValue("shadowed")}
+	val, err := lambda.EvaluateValue(vars, p)
+	if err != nil || val.Kind() != funcs.KindFunc || val.String() != lambda.String() || !val.Equal(val) {
+		t.Error("incorrect function value: ", val, err)
+	}
+
+	fn, _ := val.AsFunc()
+	res, err := fn.Call(funcs.NumberValue(decimal.NewFromInt(1)), funcs.NumberValue(decimal.NewFromInt(3)))
+	if err != nil || !res.Equal(funcs.NumberValue(decimal.NewFromInt(7))) {
+		t.Error("incorrect result of the call: ", res, err)
+	}
+	if _, err := fn.Call(funcs.Null); err == nil || err.Error() != "incorrect count of args for function '( -> ( acc,x ) ( + acc ( * x k ) ) )'. Need: 2, but get: 1" {
+		t.Error("incorrect error: ", err)
+	}
+	if _, err := lambda.Evaluate(nil, p); err == nil || err.Error() != "incorrect type of the result. Need: number, but get: function" {
+		t.Error("incorrect error: ", err)
+	}
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// LazyArg - the unevaluated argument of a lazy function bound to the scope of the call
+type LazyArg struct {
+	Exp  interfaces.Expression
+	Vars interfaces.Scope
+	Ctx  interfaces.Context
+}
+
+// Value - evaluate the argument in the scope of the call
+func (a *LazyArg) Value() (funcs.Value, error) {
+	return a.Exp.EvaluateValue(a.Vars, a.Ctx)
+}
+
+// Evaluate - evaluate the argument in the scope of the call, it must be a number or a bool
+func (a *LazyArg) Evaluate() (decimal.Decimal, error) {
+	val, err := a.Value()
+	if err != nil {
+		return decimal.Zero, err
+	}
+	num, err := val.AsNumber()
+	return num, funcs.DescribeTypeError(err, "argument '"+a.Exp.String()+"'")
+}
+
+// Expression - the argument tree
+func (a *LazyArg) Expression() interfaces.Expression {
+	return a.Exp
+}
+
+func (a *LazyArg) String() string {
+	return a.Exp.String()
+}
+
+// LazyArgs - bind the expressions to the scope of the call
+func LazyArgs(exps []interfaces.Expression, vars interfaces.Scope, p interfaces.Context) []funcs.LazyValueArg {
+	args := make([]funcs.LazyValueArg, len(exps))
+	for i, exp := range exps {
+		args[i] = &LazyArg{Exp: exp, Vars: vars, Ctx: p}
+	}
+	return args
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// List - the list literal '[a, b, c]'
+type List struct {
+	Items []interfaces.Expression
+}
+
+func (l *List) GetVarList(vars map[string]interface{}) {
+	for _, item := range l.Items {
+		item.GetVarList(vars)
+	}
+}
+
+// Evaluate - a list is not a number, so it fails unless the list is used by a function
+func (l *List) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(l, vars, p)
+}
+
+// EvaluateValue - evaluate the items in order
+func (l *List) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	items := make([]funcs.Value, len(l.Items))
+	for i, item := range l.Items {
+		val, err := item.EvaluateValue(vars, p)
+		if err != nil {
+			return funcs.Null, err
+		}
+		items[i] = val
+	}
+	return funcs.ListValue(items...), nil
+}
+
+// toString conversation
+func (l *List) String() string {
+	strs := make([]string, len(l.Items))
+	for i, item := range l.Items {
+		strs[i] = item.String()
+	}
+	return "[ " + strings.Join(strs, ",") + " ]"
+}
+
+// Index - the item of the list 'xs[i]'. The first item has the index 0, a negative index counts from the end,
+// so 'xs[-1]' is the last item
+type Index struct {
+	Exp   interfaces.Expression
+	Index interfaces.Expression
+}
+
+func (ix *Index) GetVarList(vars map[string]interface{}) {
+	ix.Exp.GetVarList(vars)
+	ix.Index.GetVarList(vars)
+}
+
+// Evaluate - return the item, it must be a number or a bool
+func (ix *Index) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(ix, vars, p)
+}
+
+// EvaluateValue - return the item of the list
+func (ix *Index) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	val, err := ix.Exp.EvaluateValue(vars, p)
+	if err != nil {
+		return funcs.Null, err
+	}
+	items, err := val.AsList()
+	if err != nil {
+		return funcs.Null, funcs.DescribeTypeError(err, "the indexed value")
+	}
+	idx, err := ix.Index.EvaluateValue(vars, p)
+	if err != nil {
+		return funcs.Null, err
+	}
+	num, err := idx.AsNumber()
+	if err != nil {
+		return funcs.Null, funcs.DescribeTypeError(err, "the index")
+	}
+	if !num.IsInteger() {
+		return funcs.Null, errors.New("index is not an integer: " + num.String())
+	}
+	i := num
+	if num.IsNegative() {
+		i = num.Add(decimal.NewFromInt(int64(len(items))))
+	}
+	if i.IsNegative() || i.GreaterThanOrEqual(decimal.NewFromInt(int64(len(items)))) {
+		return funcs.Null, errors.New("index " + num.String() + " is out of range of the list of " +
+			strconv.Itoa(len(items)) + " items")
+	}
+	return items[i.IntPart()], nil
+}
+
+// toString conversation
+func (ix *Index) String() string {
+	return "( " + ix.Exp.String() + " [ " + ix.Index.String() + " ] )"
+}
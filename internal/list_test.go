@@ -0,0 +1,78 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/arconomy/go-math-expression-parser/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestIndexEvaluateValue(t *testing.T) {
+	p := parser.NewParser()
+	vars := internal.Vars{
+		"xs": funcs.NumberList(decimal.NewFromInt(10), decimal.NewFromInt(20), decimal.NewFromInt(30)),
+		"s":  funcs.StringValue("abc"),
+	}
+	index := func(list, i string) *internal.Index {
+		return &internal.Index{Exp: &internal.Term{Val: list}, Index: &internal.Term{Val: i}}
+	}
+
+	type TestData struct {
+		exp  *internal.Index
+		need int64
+	}
+	data := []TestData{
+		{index("xs", "0"), 10},
+		{index("xs", "2"), 30},
+		{index("xs", "-1"), 30},
+		{index("xs", "-3"), 10},
+	}
+	for _, d := range data {
+		res, err := d.exp.EvaluateValue(vars, p)
+		if err != nil || !res.Equal(funcs.NumberValue(decimal.NewFromInt(d.need))) {
+			t.Error("incorrect item of "+d.exp.String()+": ", res, err)
+		}
+	}
+
+	type ErrorData struct {
+		exp *internal.Index
+		err string
+	}
+	errData := []ErrorData{
+		{index("xs", "3"), "index 3 is out of range of the list of 3 items"},
+		{index("xs", "-4"), "index -4 is out of range of the list of 3 items"},
+		{index("xs", "1.5"), "index is not an integer: 1.5"},
+		{index("xs", "null"), "incorrect type of the index. Need: number, but get: null"},
+		{index("s", "0"), "incorrect type of the indexed value. Need: list, but get: string"},
+	}
+	for _, d := range errData {
+		_, err := d.exp.EvaluateValue(vars, p)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error of "+d.exp.String()+": ", err)
+		}
+	}
+}
+
+func TestListEvaluateValue(t *testing.T) {
+	p := parser.NewParser()
+	list := &internal.List{Items: []interfaces.Expression{&internal.Term{Val: "1"}, &internal.Term{Val: "x"}}}
+	if list.String() != "[ 1,x ]" {
+		t.Error("incorrect string: " + list.String())
+	}
+	vars := map[string]interface{}{}
+	list.GetVarList(vars)
+	if _, ok := vars["x"]; !ok || len(vars) != 1 {
+		t.Error("incorrect variables: ", vars)
+	}
+
+	res, err := list.EvaluateValue(internal.Vars{"x": funcs.StringValue("a")}, p)
+	if err != nil || !res.Equal(funcs.ListValue(funcs.NumberValue(decimal.NewFromInt(1)), funcs.StringValue("a"))) {
+		t.Error("incorrect list: ", res, err)
+	}
+	if _, err := list.Evaluate(map[string]decimal.Decimal{"x": decimal.Zero}, p); err == nil {
+		t.Error("list was evaluated as a number")
+	}
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// Member - the member of the object 'order.total', members are chained: 'customer.tier.discount'
+type Member struct {
+	Exp  interfaces.Expression
+	Name string
+}
+
+// Path - the full name of the member of a variable, like "customer.tier.discount".
+// ok is false for a member of another expression, like 'orders[0].total'
+func (m *Member) Path() (path string, ok bool) {
+	switch exp := m.Exp.(type) {
+	case *Term:
+		if _, literal := ParseLiteral(exp.Val); literal || exp.Val == "" {
+			return "", false
+		}
+		return exp.Val + "." + m.Name, true
+	case *Member:
+		if path, ok := exp.Path(); ok {
+			return path + "." + m.Name, true
+		}
+	}
+	return "", false
+}
+
+// GetVarList - report the full path of the member of a variable
+func (m *Member) GetVarList(vars map[string]interface{}) {
+	if path, ok := m.Path(); ok {
+		vars[path] = struct{}{}
+		return
+	}
+	m.Exp.GetVarList(vars)
+}
+
+// Evaluate - return the member, it must be a number or a bool
+func (m *Member) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(m, vars, p)
+}
+
+// EvaluateValue - return the member of the object. A variable named by the full path, like "order.total",
+// wins over the member of the object, so numeric variables can be passed to Evaluate by their paths
+func (m *Member) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	path, isPath := m.Path()
+	if isPath {
+		if val, ok := vars.Var(path); ok {
+			return val, nil
+		}
+	}
+	val, err := m.Exp.EvaluateValue(vars, p)
+	if err != nil {
+		return funcs.Null, err
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		return funcs.Null, funcs.DescribeTypeError(err, "the object of member '"+m.Name+"'")
+	}
+	res, ok := obj.Member(m.Name)
+	if !ok {
+		if isPath {
+			return funcs.Null, errors.New("member '" + m.Name + "' is not found in '" + strings.TrimSuffix(path, "."+m.Name) + "'")
+		}
+		return funcs.Null, errors.New("member '" + m.Name + "' is not found in '" + m.Exp.String() + "'")
+	}
+	return res, nil
+}
+
+// toString conversation
+func (m *Member) String() string {
+	return "( " + m.Exp.String() + " . " + m.Name + " )"
+}
+
+// VarRoot - the variable of the path, it is "order" for "order.total"
+func VarRoot(path string) string {
+	root, _, _ := strings.Cut(path, ".")
+	return root
+}
+
+// SetVar - set the variable of the scope, the variables named by the paths of its members,
+// like "name.total", are removed, because they are the members of the previous value
+func SetVar[T any](scope map[string]T, name string, val T) {
+	for path := range scope {
+		if strings.HasPrefix(path, name+".") {
+			delete(scope, path)
+		}
+	}
+	scope[name] = val
+}
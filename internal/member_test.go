@@ -0,0 +1,76 @@
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/arconomy/go-math-expression-parser/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestMember(t *testing.T) {
+	// customer.tier.discount
+	discount := &internal.Member{Exp: &internal.Member{Exp: &internal.Term{Val: "customer"}, Name: "tier"}, Name: "discount"}
+	// items[0].price
+	price := &internal.Member{Exp: &internal.Index{Exp: &internal.Term{Val: "items"}, Index: &internal.Term{Val: "0"}}, Name: "price"}
+
+	if discount.String() != "( ( customer . tier ) . discount )" {
+		t.Error("incorrect string conversion = " + discount.String())
+	}
+	if path, ok := discount.Path(); !ok || path != "customer.tier.discount" {
+		t.Error("incorrect path: " + path)
+	}
+	if _, ok := price.Path(); ok {
+		t.Error("the member of the item has a path")
+	}
+	if v := parser.GetVarList(&internal.Node{Op: "*", LExp: discount, RExp: price}); !reflect.DeepEqual(v, []string{"customer.tier.discount", "items"}) {
+		t.Error("incorrect variables: ", v)
+	}
+
+	p := parser.NewParser()
+	num := func(s string) funcs.Value {
+		return funcs.NumberValue(decimal.RequireFromString(s))
+	}
+	vars := internal.Vars{
+		"customer": funcs.ObjectValue(funcs.MapObject{"tier": funcs.ObjectValue(funcs.MapObject{"discount": num("0.1")})}),
+		"items":    funcs.ListValue(funcs.ObjectValue(funcs.MapObject{"price": num("5")})),
+	}
+	if res, err := discount.EvaluateValue(vars, p); err != nil || !res.Equal(num("0.1")) {
+		t.Error("incorrect member: ", res, err)
+	}
+	if res, err := price.EvaluateValue(vars, p); err != nil || !res.Equal(num("5")) {
+		t.Error("incorrect member of the item: ", res, err)
+	}
+
+	// a variable named by the path wins over the member
+	res, err := discount.Evaluate(map[string]decimal.Decimal{"customer.tier.discount": decimal.NewFromInt(1)}, p)
+	if err != nil || !res.Equal(decimal.NewFromInt(1)) {
+		t.Error("incorrect value of the path: ", res, err)
+	}
+	res, err = discount.Evaluate(map[string]decimal.Decimal{"customer.tier": decimal.NewFromInt(1)}, p)
+	if err == nil || err.Error() != "incorrect type of the object of member 'discount'. Need: object, but get: number" {
+		t.Error("incorrect error: ", err)
+	}
+
+	missing := &internal.Member{Exp: &internal.Term{Val: "customer"}, Name: "name"}
+	if _, err := missing.EvaluateValue(vars, p); err == nil || err.Error() != "member 'name' is not found in 'customer'" {
+		t.Error("incorrect error: ", err)
+	}
+	missing = &internal.Member{Exp: &internal.Index{Exp: &internal.Term{Val: "items"}, Index: &internal.Term{Val: "0"}}, Name: "qty"}
+	if _, err := missing.EvaluateValue(vars, p); err == nil || err.Error() != "member 'qty' is not found in '( items [ 0 ] )'" {
+		t.Error("incorrect error: ", err)
+	}
+}
+
+func TestSetVar(t *testing.T) {
+	scope := map[string]int{"order": 1, "order.total": 2, "orders": 3, "orders.total": 4}
+	internal.SetVar(scope, "order", 5)
+	if !reflect.DeepEqual(scope, map[string]int{"order": 5, "orders": 3, "orders.total": 4}) {
+		t.Error("incorrect scope: ", scope)
+	}
+	if internal.VarRoot("order.total.net") != "order" || internal.VarRoot("order") != "order" {
+		t.Error("incorrect root of the path")
+	}
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"errors"
 
+	"github.com/arconomy/go-math-expression-parser/funcs"
 	"github.com/arconomy/go-math-expression-parser/interfaces"
 	"github.com/shopspring/decimal"
 )
@@ -15,21 +16,29 @@ type Node struct {
 }
 
 // Evaluate - execute expression tree
-func (n *Node) Evaluate(vars map[string]decimal.Decimal, p interfaces.ExpParser) (decimal.Decimal, error) {
-	left, err := n.LExp.Evaluate(vars, p)
-	if err != nil {
-		return decimal.Zero, err
+func (n *Node) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(n, vars, p)
+}
+
+// EvaluateValue - execute expression tree over typed values
+func (n *Node) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	indx, exist := BinaryOperatorExist(n.Op, p)
+	if !exist {
+		return funcs.Null, errors.New("not supported binary operation: '" + string(n.Op) + "'")
 	}
-	right, err := n.RExp.Evaluate(vars, p)
+	if lazy, ok := p.Functions().LookupLazyValue(indx, n.Op, p.Settings()); ok {
+		return lazy(LazyArgs([]interfaces.Expression{n.LExp, n.RExp}, vars, p)...)
+	}
+	left, err := n.LExp.EvaluateValue(vars, p)
 	if err != nil {
-		return decimal.Zero, err
+		return funcs.Null, err
 	}
-	indx, exist := BinaryOperatorExist(n.Op, p)
-	if !exist {
-		return decimal.Zero, errors.New("not supported binary operation: '" + string(n.Op) + "'")
+	right, err := n.RExp.EvaluateValue(vars, p)
+	if err != nil {
+		return funcs.Null, err
 	}
-	result, err := p.GetFunctions()[indx][n.Op](left, right)
-	return result, err
+	f, _ := p.Functions().LookupValue(indx, n.Op, p.Settings())
+	return f(left, right)
 }
 
 func (n *Node) GetVarList(vars map[string]interface{}) {
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// The grammar of numeric literals:
+//
+//	decimal:  digits [ '.' [ digits ] ] [ exponent ] | '.' digits [ exponent ]
+//	exponent: ( 'e' | 'E' ) [ '+' | '-' ] digits
+//	hex:      '0' ( 'x' | 'X' ) hexdigits
+//	binary:   '0' ( 'b' | 'B' ) bindigits
+//
+// A single '_' may separate two digits: 1_000_000, 0xFF_FF, 0b1010_0101.
+// It is used by the tokenizer, by Term.Evaluate and by Term.GetVarList,
+// so they always agree on what is a number
+
+// ScanNumber - return the length of the longest numeric literal at the beginning of s, 0 if there is no literal
+func ScanNumber(s string) int {
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X':
+			if n := scanDigits(s[2:], isHexDigit); n > 0 {
+				return 2 + n
+			}
+		case 'b', 'B':
+			if n := scanDigits(s[2:], isBinDigit); n > 0 {
+				return 2 + n
+			}
+		}
+	}
+
+	n := scanDigits(s, isDecDigit)
+	if n < len(s) && s[n] == '.' {
+		frac := scanDigits(s[n+1:], isDecDigit)
+		if n == 0 && frac == 0 {
+			return 0
+		}
+		n += 1 + frac
+	}
+	if n == 0 {
+		return 0
+	}
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		exp := n + 1
+		if exp < len(s) && (s[exp] == '+' || s[exp] == '-') {
+			exp++
+		}
+		if digits := scanDigits(s[exp:], isDecDigit); digits > 0 {
+			n = exp + digits
+		}
+	}
+	return n
+}
+
+// scanDigits - return the length of the digits at the beginning of s, a single '_' may separate two digits
+func scanDigits(s string, isDigit func(c byte) bool) int {
+	n := 0
+	for n < len(s) {
+		if isDigit(s[n]) {
+			n++
+		} else if s[n] == '_' && n > 0 && n+1 < len(s) && isDigit(s[n+1]) {
+			n += 2
+		} else {
+			break
+		}
+	}
+	return n
+}
+
+// ParseNumber - convert the numeric literal with an optional leading '-' to decimal.Decimal,
+// return false if s is not a literal
+func ParseNumber(s string) (decimal.Decimal, bool) {
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	if s == "" || ScanNumber(s) != len(s) {
+		return decimal.Zero, false
+	}
+	s = strings.ReplaceAll(s, "_", "")
+
+	var res decimal.Decimal
+	if base := literalBase(s); base != 10 {
+		i, ok := new(big.Int).SetString(s[2:], base)
+		if !ok {
+			return decimal.Zero, false
+		}
+		res = decimal.NewFromBigInt(i, 0)
+	} else {
+		d, err := decimal.NewFromString(s)
+		if err != nil {
+			// the exponent is out of range
+			return decimal.Zero, false
+		}
+		res = d
+	}
+	if neg {
+		res = res.Neg()
+	}
+	return res, true
+}
+
+// IsNumber - checks that s is a numeric literal with an optional leading '-'
+func IsNumber(s string) bool {
+	_, ok := ParseNumber(s)
+	return ok
+}
+
+func literalBase(s string) int {
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X':
+			return 16
+		case 'b', 'B':
+			return 2
+		}
+	}
+	return 10
+}
+
+func isDecDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDecDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func isBinDigit(c byte) bool {
+	return c == '0' || c == '1'
+}
@@ -0,0 +1,91 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/shopspring/decimal"
+)
+
+func TestScanNumber(t *testing.T) {
+	type TestData struct {
+		input string
+		n     int
+	}
+
+	data := []TestData{
+		{"", 0},
+		{"x", 0},
+		{".", 0},
+		{"_1", 0},
+		{"12+3", 2},
+		{"1.5", 3},
+		{".5", 2},
+		{"1.", 2},
+		{"1.5.3", 3},
+		{"1.5e-3", 6},
+		{"1.5E+3", 6},
+		{"2e3x", 3},
+		{"2e", 1},
+		{"2e+", 1},
+		{"2ex", 1},
+		{"1_000_000", 9},
+		{"1__0", 1},
+		{"1_", 1},
+		{"1_000.000_1", 11},
+		{"1e1_0", 5},
+		{"0xFF", 4},
+		{"0xff_ff", 7},
+		{"0x", 1},
+		{"0xg", 1},
+		{"0b1010", 6},
+		{"0b1012", 5},
+		{"0b2", 1},
+		{"00x1", 2},
+	}
+
+	for _, d := range data {
+		if n := internal.ScanNumber(d.input); n != d.n {
+			t.Errorf("incorrect length of the literal in '%s', need: %d, but get: %d", d.input, d.n, n)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+	}
+
+	data := []TestData{
+		{"0", "0"},
+		{"1.5", "1.5"},
+		{"-1.5", "-1.5"},
+		{".5", "0.5"},
+		{"1.", "1"},
+		{"1.5e-3", "0.0015"},
+		{"15E2", "1500"},
+		{"1_000_000", "1000000"},
+		{"0xFF", "255"},
+		{"0XfF_01", "65281"},
+		{"0b1010_0101", "165"},
+		{"-0x10", "-16"},
+	}
+
+	for _, d := range data {
+		res, ok := internal.ParseNumber(d.input)
+		if !ok {
+			t.Error("literal was not parsed: " + d.input)
+			continue
+		}
+		if !res.Equal(decimal.RequireFromString(d.output)) {
+			t.Error("incorrect value of '" + d.input + "', need: " + d.output + ", but get: " + res.String())
+		}
+	}
+
+	for _, s := range []string{"", "-", "x", "1_", "1e", "0x", "1.5.3", "1 2", "+1", "Inf", "NaN", "1e99999999999"} {
+		if internal.IsNumber(s) {
+			t.Error("incorrect literal was parsed: " + s)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// Postfix - the struct which contains a variable and a postfix operation, like 'n!'
+type Postfix struct {
+	Op  string
+	Exp interfaces.Expression
+}
+
+func (pf *Postfix) GetVarList(vars map[string]interface{}) {
+	pf.Exp.GetVarList(vars)
+}
+
+// Evaluate - execute postfix operator
+func (pf *Postfix) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(pf, vars, p)
+}
+
+// EvaluateValue - execute postfix operator, its operand must be a number or a bool
+func (pf *Postfix) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	f, exist := p.Functions().LookupPostfix(pf.Op)
+	if !exist {
+		return funcs.Null, errors.New("not supported postfix operation: '" + pf.Op + "'")
+	}
+	val, err := pf.Exp.EvaluateValue(vars, p)
+	if err != nil {
+		return funcs.Null, err
+	}
+	args, err := funcs.NumberArgs(pf.Op, []funcs.Value{val})
+	if err != nil {
+		return funcs.Null, err
+	}
+	res, err := f(args...)
+	if err != nil {
+		return funcs.Null, err
+	}
+	return funcs.NumberValue(res), nil
+}
+
+// toString conversation, the operator follows the operand
+func (pf *Postfix) String() string {
+	return "( " + pf.Exp.String() + " " + pf.Op + " )"
+}
@@ -0,0 +1,42 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/arconomy/go-math-expression-parser/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestPostfix(t *testing.T) {
+	p := parser.NewParser()
+	square := func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return args[0].Mul(args[0]), nil
+	}
+	if err := p.RegisterOperator(funcs.Operator{Name: "²", Fixity: funcs.Postfix, Func: square}); err != nil {
+		t.Fatal(err)
+	}
+
+	pf := internal.Postfix{Op: "²", Exp: &internal.Term{Val: "a"}}
+	if pf.String() != "( a ² )" {
+		t.Error("incorrect string conversion = " + pf.String())
+	}
+
+	vars := map[string]interface{}{}
+	pf.GetVarList(vars)
+	if _, ok := vars["a"]; !ok || len(vars) != 1 {
+		t.Error("incorrect variables: ", vars)
+	}
+
+	res, err := pf.Evaluate(map[string]decimal.Decimal{"a": decimal.NewFromInt(-3)}, p)
+	if err != nil || !res.Equal(decimal.NewFromInt(9)) {
+		t.Error("incorrect result = " + res.String())
+	}
+
+	// the operand is not evaluated without the operator
+	pf = internal.Postfix{Op: "³", Exp: &internal.Term{Val: "a"}}
+	if _, err := pf.Evaluate(nil, p); err == nil || err.Error() != "not supported postfix operation: '³'" {
+		t.Error("incorrect error handling: ", err)
+	}
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// Vars - the scope with the variables of the map
+type Vars map[string]funcs.Value
+
+// Var - the value of the key name
+func (v Vars) Var(name string) (funcs.Value, bool) {
+	val, ok := v[name]
+	return val, ok
+}
+
+// NumberVars - the scope with the numeric variables, a variable is converted to the value on lookup,
+// so the map is not copied for every evaluation
+type NumberVars map[string]decimal.Decimal
+
+// Var - the number of the key name
+func (v NumberVars) Var(name string) (funcs.Value, bool) {
+	num, ok := v[name]
+	if !ok {
+		return funcs.Null, false
+	}
+	return funcs.NumberValue(num), true
+}
+
+// LocalScope - the local variables, like the parameters of a function or the assigned variable of a script,
+// which shadow the variables of the parent scope and the paths of their members, like "x.total" for x.
+// It is not changed after its creation, so a closure captures the scope without copying
+type LocalScope struct {
+	Parent interfaces.Scope
+	Vars   Vars
+}
+
+// Var - the local variable or the variable of the parent scope
+func (s *LocalScope) Var(name string) (funcs.Value, bool) {
+	if val, ok := s.Vars[name]; ok {
+		return val, true
+	}
+	if _, ok := s.Vars[VarRoot(name)]; ok {
+		// the path of a member of the previous value of the local variable
+		return funcs.Null, false
+	}
+	return s.Parent.Var(name)
+}
@@ -0,0 +1,44 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/shopspring/decimal"
+)
+
+func TestLocalScope(t *testing.T) {
+	parent := internal.Vars{"x": funcs.StringValue("parent"), "y": funcs.StringValue("y"), "x.total": funcs.StringValue("path")}
+	scope := &internal.LocalScope{Parent: parent, Vars: internal.Vars{"x": funcs.StringValue("local")}}
+
+	if val, ok := scope.Var("x"); !ok || !val.Equal(funcs.StringValue("local")) {
+		t.Error("the local variable doesn't shadow the parent one: ", val)
+	}
+	if val, ok := scope.Var("y"); !ok || !val.Equal(funcs.StringValue("y")) {
+		t.Error("incorrect variable of the parent scope: ", val)
+	}
+	// the path is the member of the previous value of x
+	if val, ok := scope.Var("x.total"); ok {
+		t.Error("the path of the shadowed variable is found: ", val)
+	}
+	if _, ok := scope.Var("z"); ok {
+		t.Error("unknown variable is found")
+	}
+	if val, ok := parent.Var("x.total"); !ok || !val.Equal(funcs.StringValue("path")) {
+		t.Error("the parent scope was changed: ", val)
+	}
+}
+
+func TestNumberVars(t *testing.T) {
+	vars := internal.NumberVars{"x": decimal.NewFromInt(2)}
+	if val, ok := vars.Var("x"); !ok || !val.Equal(funcs.NumberValue(decimal.NewFromInt(2))) {
+		t.Error("incorrect variable: ", val)
+	}
+	if _, ok := vars.Var("y"); ok {
+		t.Error("unknown variable is found")
+	}
+	if _, ok := internal.NumberVars(nil).Var("x"); ok {
+		t.Error("variable of nil map is found")
+	}
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// Assign - the script statement 'name = exp', its value is the value of exp.
+// The variable is stored by the Script, which contains the statement
+type Assign struct {
+	Name string
+	Exp  interfaces.Expression
+}
+
+func (a *Assign) GetVarList(vars map[string]interface{}) {
+	a.Exp.GetVarList(vars)
+}
+
+// Evaluate - return the value of the assigned expression
+func (a *Assign) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(a, vars, p)
+}
+
+// EvaluateValue - return the typed value of the assigned expression
+func (a *Assign) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	return a.Exp.EvaluateValue(vars, p)
+}
+
+// toString conversation
+func (a *Assign) String() string {
+	return "( = " + a.Name + " " + a.Exp.String() + " )"
+}
+
+// Script - the statements which are evaluated in order, the result is the value of the last one.
+// Assigned variables are local: they are visible to the next statements and shadow the variables of the caller
+type Script struct {
+	Statements []interfaces.Expression
+}
+
+// GetVarList - report the external inputs only, a variable which is read after its assignment is local
+func (s *Script) GetVarList(vars map[string]interface{}) {
+	assigned := make(map[string]struct{})
+	for _, st := range s.Statements {
+		used := make(map[string]interface{})
+		st.GetVarList(used)
+		for name := range used {
+			if _, ok := assigned[VarRoot(name)]; !ok {
+				vars[name] = struct{}{}
+			}
+		}
+		if a, ok := st.(*Assign); ok {
+			assigned[a.Name] = struct{}{}
+		}
+	}
+}
+
+// Evaluate - execute the statements, the map of the caller is not changed
+func (s *Script) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(s, vars, p)
+}
+
+// EvaluateValue - execute the statements over typed values, the map of the caller is not changed
+func (s *Script) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
+	scope := vars
+	res := funcs.NumberValue(decimal.Zero)
+	for _, st := range s.Statements {
+		val, err := st.EvaluateValue(scope, p)
+		if err != nil {
+			return funcs.Null, err
+		}
+		if a, ok := st.(*Assign); ok {
+			// every assignment makes a new scope, so a closure keeps the variables of its creation
+			scope = &LocalScope{Parent: scope, Vars: Vars{a.Name: val}}
+		}
+		res = val
+	}
+	return res, nil
+}
+
+// toString conversation, statements are separated by '; '
+func (s *Script) String() string {
+	strs := make([]string, len(s.Statements))
+	for i, st := range s.Statements {
+		strs[i] = st.String()
+	}
+	return strings.Join(strs, "; ")
+}
@@ -0,0 +1,41 @@
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/arconomy/go-math-expression-parser/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestScript(t *testing.T) {
+	// x = a * 2; y = x + b; y * x
+	script := internal.Script{Statements: []interfaces.Expression{
+		&internal.Assign{Name: "x", Exp: &internal.Node{Op: "*", LExp: &internal.Term{Val: "a"}, RExp: &internal.Term{Val: "2"}}},
+		&internal.Assign{Name: "y", Exp: &internal.Node{Op: "+", LExp: &internal.Term{Val: "x"}, RExp: &internal.Term{Val: "b"}}},
+		&internal.Node{Op: "*", LExp: &internal.Term{Val: "y"}, RExp: &internal.Term{Val: "x"}},
+	}}
+	if script.String() != "( = x ( * a 2 ) ); ( = y ( + x b ) ); ( * y x )" {
+		t.Error("incorrect string conversion = " + script.String())
+	}
+
+	if v := parser.GetVarList(&script); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Error("incorrect variables: ", v)
+	}
+
+	vars := map[string]decimal.Decimal{"a": decimal.NewFromInt(3), "b": decimal.NewFromInt(1), "x": decimal.NewFromInt(100)}
+	res, err := script.Evaluate(vars, parser.NewParser())
+	if err != nil || !res.Equal(decimal.NewFromInt(42)) {
+		t.Error("incorrect result = " + res.String())
+	}
+	if !vars["x"].Equal(decimal.NewFromInt(100)) || len(vars) != 3 {
+		t.Error("the variables of the caller were changed: ", vars)
+	}
+
+	_, err = script.Evaluate(map[string]decimal.Decimal{"a": decimal.NewFromInt(3)}, parser.NewParser())
+	if err == nil {
+		t.Error("incorrect error handling!")
+	}
+}
@@ -2,13 +2,13 @@ package internal
 
 import (
 	"errors"
-	"strconv"
 
+	"github.com/arconomy/go-math-expression-parser/funcs"
 	"github.com/arconomy/go-math-expression-parser/interfaces"
 	"github.com/shopspring/decimal"
 )
 
-// Term - the struct which contains a single value
+// Term - the struct which contains a single value: a literal or a name of a variable
 type Term struct {
 	Val string
 }
@@ -17,7 +17,7 @@ func (t *Term) GetVarList(vars map[string]interface{}) {
 	if t.Val == "" {
 		return
 	}
-	if _, err := strconv.ParseFloat(t.Val, 64); err == nil {
+	if _, ok := ParseLiteral(t.Val); ok {
 		return
 	}
 	vars[t.Val] = struct{}{}
@@ -25,16 +25,21 @@ func (t *Term) GetVarList(vars map[string]interface{}) {
 }
 
 // Evaluate - return a value which contains in Term
-func (t *Term) Evaluate(vars map[string]decimal.Decimal, p interfaces.ExpParser) (decimal.Decimal, error) {
+func (t *Term) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(t, vars, p)
+}
+
+// EvaluateValue - return the literal or the value of the variable
+func (t *Term) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
 	if t.Val == "" {
-		return decimal.Zero, nil
+		return funcs.NumberValue(decimal.Zero), nil
 	}
-	if val, err := decimal.NewFromString(t.Val); err == nil {
+	if val, ok := ParseLiteral(t.Val); ok {
 		return val, nil
 	}
-	val, ok := vars[t.Val]
+	val, ok := vars.Var(t.Val)
 	if !ok {
-		return decimal.Zero, errors.New("value '" + t.Val + " not found in map")
+		return funcs.Null, errors.New("value '" + t.Val + " not found in map")
 	}
 	return val, nil
 }
@@ -3,6 +3,7 @@ package internal
 import (
 	"errors"
 
+	"github.com/arconomy/go-math-expression-parser/funcs"
 	"github.com/arconomy/go-math-expression-parser/interfaces"
 	"github.com/shopspring/decimal"
 )
@@ -18,17 +19,25 @@ func (u *Unary) GetVarList(vars map[string]interface{}) {
 }
 
 // Evaluate - execute unary operator
-func (u *Unary) Evaluate(vars map[string]decimal.Decimal, p interfaces.ExpParser) (decimal.Decimal, error) {
-	val, err := u.Exp.Evaluate(vars, p)
-	if err != nil {
-		return decimal.Zero, err
-	}
+func (u *Unary) Evaluate(vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	return EvaluateNumber(u, vars, p)
+}
+
+// EvaluateValue - execute unary operator over typed values
+func (u *Unary) EvaluateValue(vars interfaces.Scope, p interfaces.Context) (funcs.Value, error) {
 	indx, exist := UnaryOperatorExist(u.Op, p)
 	if !exist {
-		return decimal.Zero, errors.New("not supported unary operation: '" + u.Op + "'")
+		return funcs.Null, errors.New("not supported unary operation: '" + u.Op + "'")
+	}
+	if lazy, ok := p.Functions().LookupLazyValue(indx, u.Op, p.Settings()); ok {
+		return lazy(LazyArgs([]interfaces.Expression{u.Exp}, vars, p)...)
+	}
+	val, err := u.Exp.EvaluateValue(vars, p)
+	if err != nil {
+		return funcs.Null, err
 	}
-	result, err := p.GetFunctions()[indx][u.Op](val)
-	return result, err
+	f, _ := p.Functions().LookupValue(indx, u.Op, p.Settings())
+	return f(val)
 }
 
 // toString conversation
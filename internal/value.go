@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// ParseLiteral - convert the literal to the value: a number, true, false, null or a quoted string
+// with Go escapes, like "a\tb". Return false if s is not a literal
+func ParseLiteral(s string) (funcs.Value, bool) {
+	if num, ok := ParseNumber(s); ok {
+		return funcs.NumberValue(num), true
+	}
+	switch s {
+	case "true":
+		return funcs.BoolValue(true), true
+	case "false":
+		return funcs.BoolValue(false), true
+	case "null":
+		return funcs.Null, true
+	}
+	if strings.HasPrefix(s, "\"") {
+		if str, err := strconv.Unquote(s); err == nil {
+			return funcs.StringValue(str), true
+		}
+	}
+	return funcs.Null, false
+}
+
+// EvaluateNumber - evaluate the expression with numeric variables, a bool result is converted to 1 or 0.
+// Other types of the result are a *funcs.TypeError
+func EvaluateNumber(exp interfaces.Expression, vars map[string]decimal.Decimal, p interfaces.Context) (decimal.Decimal, error) {
+	val, err := exp.EvaluateValue(NumberVars(vars), p)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	num, err := val.AsNumber()
+	return num, funcs.DescribeTypeError(err, "the result")
+}
@@ -0,0 +1,61 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/arconomy/go-math-expression-parser/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseLiteral(t *testing.T) {
+	type TestData struct {
+		input string
+		ok    bool
+		need  funcs.Value
+	}
+
+	data := []TestData{
+		{"0x10", true, funcs.NumberValue(decimal.NewFromInt(16))},
+		{"true", true, funcs.BoolValue(true)},
+		{"false", true, funcs.BoolValue(false)},
+		{"null", true, funcs.Null},
+		{`"a\tb"`, true, funcs.StringValue("a\tb")},
+		{`""`, true, funcs.StringValue("")},
+		{"True", false, funcs.Null},
+		{`"a`, false, funcs.Null},
+		{"x", false, funcs.Null},
+	}
+
+	for _, d := range data {
+		val, ok := internal.ParseLiteral(d.input)
+		if ok != d.ok || !val.Equal(d.need) {
+			t.Error("incorrect literal '" + d.input + "': " + val.String())
+		}
+	}
+}
+
+func TestTermEvaluateValue(t *testing.T) {
+	p := parser.NewParser()
+	vars := internal.Vars{"code": funcs.StringValue("USD")}
+
+	res, err := (&internal.Term{Val: `"EUR"`}).EvaluateValue(vars, p)
+	if err != nil || !res.Equal(funcs.StringValue("EUR")) {
+		t.Error("incorrect result: ", res, err)
+	}
+	res, err = (&internal.Term{Val: "code"}).EvaluateValue(vars, p)
+	if err != nil || !res.Equal(funcs.StringValue("USD")) {
+		t.Error("incorrect result: ", res, err)
+	}
+
+	// the numeric evaluation fails if the value is not a number
+	_, err = internal.EvaluateNumber(&internal.Term{Val: `"EUR"`}, nil, p)
+	if err == nil || err.Error() != "incorrect type of the result. Need: number, but get: string" {
+		t.Error("incorrect error: ", err)
+	}
+	num, err := internal.EvaluateNumber(&internal.Term{Val: "true"}, nil, p)
+	if err != nil || !num.Equal(funcs.True) {
+		t.Error("incorrect result: ", num, err)
+	}
+}
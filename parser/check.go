@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/arconomy/go-math-expression-parser/internal"
+)
+
+// TypeCheckError - the error found by CheckTypes. The position points to the token of the node:
+// the operator, the function name or the operand
+type TypeCheckError struct {
+	ParseError
+	Type *funcs.TypeError // the needed and the actual types, nil if the error is not about types, like an unknown variable
+}
+
+// CheckTypes - infer the type of the expression without its evaluation. vars declares the types of the variables,
+// funcs.KindAny is the type of a variable which can have any type. The arguments of operators and functions
+// are checked with their signatures from the registry, see funcs.Signature.
+// All errors are returned in the order of their positions, the result type is funcs.KindAny if it is unknown
+func (e *CompiledExpression) CheckTypes(vars map[string]funcs.Kind) (funcs.Kind, []*TypeCheckError) {
+	c := &typeChecker{exp: e, items: make(map[interfaces.Expression][]funcs.Kind)}
+	res := c.check(e.root, vars)
+	sort.SliceStable(c.errs, func(i, j int) bool {
+		return c.errs[i].Offset < c.errs[j].Offset
+	})
+	return res, c.errs
+}
+
+// typeChecker - collects the type errors of the compiled expression
+type typeChecker struct {
+	exp   *CompiledExpression
+	errs  []*TypeCheckError
+	items map[interfaces.Expression][]funcs.Kind // the types of the items of list literals
+}
+
+// check - the type of the node, vars are the types of the variables in the scope of the node
+func (c *typeChecker) check(exp interfaces.Expression, vars map[string]funcs.Kind) funcs.Kind {
+	functions := c.exp.ctx.Functions()
+	switch n := exp.(type) {
+	case *internal.Term:
+		if n.Val == "" {
+			return funcs.KindNumber
+		}
+		if val, ok := internal.ParseLiteral(n.Val); ok {
+			return val.Kind()
+		}
+		if k, ok := vars[n.Val]; ok {
+			return k
+		}
+		c.fail(exp, nil, "unknown variable '"+n.Val+"'")
+
+	case *internal.Node:
+		args := []funcs.Kind{c.check(n.LExp, vars), c.check(n.RExp, vars)}
+		level, ok := internal.BinaryOperatorExist(n.Op, c.exp.ctx)
+		if !ok {
+			c.fail(exp, nil, "not supported binary operation: '"+n.Op+"'")
+			break
+		}
+		sig, _ := functions.Signature(level, n.Op)
+		return c.apply(exp, n.Op, sig, args)
+
+	case *internal.Unary:
+		args := []funcs.Kind{c.check(n.Exp, vars)}
+		sig, ok := functions.Signature(0, n.Op)
+		if !ok {
+			c.fail(exp, nil, "not supported unary operation: '"+n.Op+"'")
+			break
+		}
+		return c.apply(exp, n.Op, sig, args)
+
+	case *internal.Postfix:
+		args := []funcs.Kind{c.check(n.Exp, vars)}
+		sig, ok := functions.PostfixSignature(n.Op)
+		if !ok {
+			c.fail(exp, nil, "not supported postfix operation: '"+n.Op+"'")
+			break
+		}
+		return c.apply(exp, n.Op, sig, args)
+
+	case interfaces.Function:
+		var args []funcs.Kind
+		for _, arg := range n.GetArgs() {
+			args = append(args, c.check(arg, vars))
+		}
+		sig, ok := functions.Signature(0, n.GetOperation())
+		if !ok {
+			c.fail(exp, nil, "function '"+n.GetOperation()+"' is not supported")
+			break
+		}
+		if sig.NumericItems {
+			c.checkItems(exp, n.GetOperation(), n.GetArgs())
+		}
+		return c.apply(exp, n.GetOperation(), sig, args)
+
+	case *internal.List:
+		kinds := make([]funcs.Kind, len(n.Items))
+		for i, item := range n.Items {
+			kinds[i] = c.check(item, vars)
+		}
+		c.items[exp] = kinds
+		return funcs.KindList
+
+	case *internal.Member:
+		// a variable named by the full path is declared without its object
+		if path, ok := n.Path(); ok {
+			if k, ok := vars[path]; ok {
+				return k
+			}
+		}
+		obj := c.check(n.Exp, vars)
+		if !obj.AssignableTo(funcs.KindObject) {
+			c.fail(exp, &funcs.TypeError{What: "the object of member '" + n.Name + "'", Need: funcs.KindObject, Get: obj}, "")
+		}
+
+	case *internal.Index:
+		list, index := c.check(n.Exp, vars), c.check(n.Index, vars)
+		if !list.AssignableTo(funcs.KindList) {
+			c.fail(exp, &funcs.TypeError{What: "the indexed value", Need: funcs.KindList, Get: list}, "")
+		}
+		if !index.AssignableTo(funcs.KindNumber) {
+			c.fail(exp, &funcs.TypeError{What: "the index", Need: funcs.KindNumber, Get: index}, "")
+		}
+		// the item of a list literal has the common type of its items
+		if kinds, ok := c.items[n.Exp]; ok && len(kinds) > 0 {
+			res := kinds[0]
+			for _, k := range kinds[1:] {
+				res = funcs.CommonKind(res, k)
+			}
+			return res
+		}
+
+	case *internal.Lambda:
+		// the parameters can have any type, like the parameters of a defined function
+		scope := make(map[string]funcs.Kind, len(vars)+len(n.Params))
+		for name, k := range vars {
+			scope[name] = k
+		}
+		for _, param := range n.Params {
+			internal.SetVar(scope, param, funcs.KindAny)
+		}
+		c.check(n.Body, scope)
+		return funcs.KindFunc
+
+	case *internal.Assign:
+		return c.check(n.Exp, vars)
+
+	case *internal.Script:
+		// assigned variables are local, like in Script.EvaluateValue
+		scope := make(map[string]funcs.Kind, len(vars)+len(n.Statements))
+		for name, k := range vars {
+			scope[name] = k
+		}
+		res := funcs.KindNumber
+		for _, st := range n.Statements {
+			res = c.check(st, scope)
+			if a, ok := st.(*internal.Assign); ok {
+				internal.SetVar(scope, a.Name, res)
+			}
+		}
+		return res
+	}
+	return funcs.KindAny
+}
+
+// apply - the result type of the call, the mismatched arguments are reported at the node
+func (c *typeChecker) apply(exp interfaces.Expression, name string, sig funcs.Signature, args []funcs.Kind) funcs.Kind {
+	res, errs := sig.Apply(name, args)
+	for _, err := range errs {
+		te, _ := err.(*funcs.TypeError)
+		c.fail(exp, te, err.Error())
+	}
+	return res
+}
+
+// checkItems - checks that the items of list literals of the arguments are numbers or bools,
+// like the items of sum([1, 2]), the errors point to the function
+func (c *typeChecker) checkItems(exp interfaces.Expression, name string, args []interfaces.Expression) {
+	for i, arg := range args {
+		for j, k := range c.items[arg] {
+			if !k.AssignableTo(funcs.KindNumber) {
+				c.fail(exp, &funcs.TypeError{What: "item " + strconv.Itoa(j+1) + " of argument " + strconv.Itoa(i+1) + " of '" + name + "'",
+					Need: funcs.KindNumber, Get: k}, "")
+			}
+		}
+	}
+}
+
+// fail - add the error at the token of the node, msg is the message of te if it is empty
+func (c *typeChecker) fail(exp interfaces.Expression, te *funcs.TypeError, msg string) {
+	if msg == "" {
+		msg = te.Error()
+	}
+	t := c.exp.positions[exp]
+	err := &TypeCheckError{ParseError: ParseError{Offset: t.pos, Found: t.text, Msg: msg}, Type: te}
+	err.locate(c.exp.source)
+	c.errs = append(c.errs, err)
+}
@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckTypes(t *testing.T) {
+	type TestData struct {
+		input string
+		res   funcs.Kind
+	}
+	data := []TestData{
+		{"price * qty", funcs.KindNumber},
+		{`currency == "USD"`, funcs.KindBool},
+		{`vip ? "gold" : "basic"`, funcs.KindString},
+		{`if(vip, price, null)`, funcs.KindNumber},
+		{`if(vip, price, currency)`, funcs.KindAny},
+		{`concat(upper(currency), " ", price)`, funcs.KindString},
+		{"coalesce(discount, 0.1) * price", funcs.KindNumber},
+		{"vip && qty > 1", funcs.KindBool},
+		{"vip + 1", funcs.KindNumber},
+		{"-qty!", funcs.KindNumber},
+		{`discount == null`, funcs.KindBool},
+		{`"a" < currency`, funcs.KindBool},
+		{"len(currency) > 3 || !vip", funcs.KindBool},
+		{"sum(prices) * (1 - qty)", funcs.KindNumber},
+		{"[price, qty]", funcs.KindList},
+		{"prices[0] * 2", funcs.KindNumber},
+		{"sum([1, vip, price])", funcs.KindNumber},
+		{"avg([true])", funcs.KindNumber},
+		{"[price, 2][0]", funcs.KindNumber},
+		{`[price, "a"][1]`, funcs.KindAny},
+		{`count([1, "a"])`, funcs.KindNumber},
+		{"count(prices, currency)", funcs.KindNumber},
+		{"map(prices, p -> p * qty)", funcs.KindList},
+		{"reduce(prices, 0, (acc, p) -> acc + p)", funcs.KindAny},
+		{"x -> x", funcs.KindFunc},
+		{"order.total * 2", funcs.KindNumber},
+		{"order.customer.tier", funcs.KindAny},
+		{"sum(map(prices, p -> p.net))", funcs.KindNumber},
+		{"", funcs.KindNumber},
+	}
+
+	p := NewParser()
+	vars := map[string]funcs.Kind{
+		"price":    funcs.KindNumber,
+		"qty":      funcs.KindNumber,
+		"currency": funcs.KindString,
+		"vip":      funcs.KindBool,
+		"discount": funcs.KindAny,
+		"prices":   funcs.KindList,
+		"order":    funcs.KindObject,
+		// a member can be declared by its path
+		"order.total": funcs.KindNumber,
+	}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		res, errs := exp.CheckTypes(vars)
+		if len(errs) != 0 {
+			t.Error("incorrect errors for '"+d.input+"': ", errs)
+		}
+		if res != d.res {
+			t.Error("incorrect type of '" + d.input + "': " + res.String() + ", need: " + d.res.String())
+		}
+	}
+
+	// the types of local variables are inferred
+	exp, _ := p.ParseScript("label = concat(currency, price)\nlen(label) * qty")
+	if res, errs := exp.CheckTypes(vars); res != funcs.KindNumber || len(errs) != 0 {
+		t.Error("incorrect type of the script: ", res, errs)
+	}
+	exp, _ = p.ParseScript("label = concat(currency, price)\nlabel * qty")
+	if _, errs := exp.CheckTypes(vars); len(errs) != 1 || errs[0].Error() != "incorrect type of argument 1 of '*'. Need: number, but get: string at line 2, column 7" {
+		t.Error("incorrect errors of the script: ", errs)
+	}
+
+	// the result type of a defined function is inferred from its body
+	if err := p.DefineFunction(`label(c, x) = concat(c, " ", x)`); err != nil {
+		t.Fatal(err)
+	}
+	exp, _ = p.Parse("label(currency, price) * 2")
+	if _, errs := exp.CheckTypes(vars); len(errs) != 1 || errs[0].Type == nil || errs[0].Type.Get != funcs.KindString {
+		t.Error("incorrect errors of the defined function: ", errs)
+	}
+}
+
+func TestCheckTypesErrors(t *testing.T) {
+	type TestError struct {
+		line   int
+		column int
+		found  string
+		msg    string
+	}
+	type TestData struct {
+		input string
+		errs  []TestError
+	}
+	data := []TestData{
+		{`price + "USD"`, []TestError{
+			{1, 7, "+", "incorrect type of argument 2 of '+'. Need: number, but get: string"},
+		}},
+		// all errors are reported in the order of positions
+		{"sqrt(currency) + -currency\n\t* upper(price)", []TestError{
+			{1, 1, "sqrt", "incorrect type of argument 1 of 'sqrt'. Need: number, but get: string"},
+			{1, 18, "-", "incorrect type of argument 1 of '-'. Need: number, but get: string"},
+			{2, 2, "*", "incorrect type of argument 2 of '*'. Need: number, but get: string"},
+			{2, 4, "upper", "incorrect type of argument 1 of 'upper'. Need: string, but get: number"},
+		}},
+		{`currency == 1`, []TestError{
+			{1, 10, "==", "incorrect type of argument 2 of '=='. Need: string, but get: number"},
+		}},
+		{`qty < currency`, []TestError{
+			{1, 5, "<", "incorrect type of argument 2 of '<'. Need: number, but get: string"},
+		}},
+		{`currency ? 1 : 2`, []TestError{
+			{1, 10, "?", "incorrect type of argument 1 of 'if'. Need: bool, but get: string"},
+		}},
+		{`null!`, []TestError{
+			{1, 5, "!", "incorrect type of argument 1 of '!'. Need: number, but get: null"},
+		}},
+		{`currency[0] + [1, 2][currency]`, []TestError{
+			{1, 9, "[", "incorrect type of the indexed value. Need: list, but get: string"},
+			{1, 21, "[", "incorrect type of the index. Need: number, but get: string"},
+		}},
+		{`map(price, p -> p * "x")`, []TestError{
+			{1, 1, "map", "incorrect type of argument 1 of 'map'. Need: list, but get: number"},
+			{1, 19, "*", "incorrect type of argument 2 of '*'. Need: number, but get: string"},
+		}},
+		// the items of list literals of aggregates are checked
+		{`sum([1, "a"])`, []TestError{
+			{1, 1, "sum", "incorrect type of item 2 of argument 1 of 'sum'. Need: number, but get: string"},
+		}},
+		{`max(1, [currency, [2]]) + ["a", "b"][0]`, []TestError{
+			{1, 1, "max", "incorrect type of item 1 of argument 2 of 'max'. Need: number, but get: string"},
+			{1, 1, "max", "incorrect type of item 2 of argument 2 of 'max'. Need: number, but get: list"},
+			{1, 25, "+", "incorrect type of argument 2 of '+'. Need: number, but get: string"},
+		}},
+		{`filter([1], 2)`, []TestError{
+			{1, 1, "filter", "incorrect type of argument 2 of 'filter'. Need: function, but get: number"},
+		}},
+		{`price.net + order.total`, []TestError{
+			{1, 7, "net", "incorrect type of the object of member 'net'. Need: object, but get: number"},
+			{1, 13, "order", "unknown variable 'order'"},
+		}},
+		{`total * 2 + "x"`, []TestError{
+			{1, 1, "total", "unknown variable 'total'"},
+			{1, 11, "+", "incorrect type of argument 2 of '+'. Need: number, but get: string"},
+		}},
+	}
+
+	p := NewParser()
+	vars := map[string]funcs.Kind{"price": funcs.KindNumber, "qty": funcs.KindNumber, "currency": funcs.KindString}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		_, errs := exp.CheckTypes(vars)
+		if len(errs) != len(d.errs) {
+			t.Error("incorrect count of errors for '"+d.input+"': ", errs)
+			continue
+		}
+		for i, e := range d.errs {
+			err := errs[i]
+			if err.Line != e.line || err.Column != e.column || err.Found != e.found || err.Msg != e.msg {
+				t.Error("incorrect error for '" + d.input + "': " + err.Error() + " (" + err.Found + ")")
+			}
+		}
+	}
+
+	exp, _ := p.Parse(`price + "USD"`)
+	_, errs := exp.CheckTypes(vars)
+	if errs[0].Type == nil || errs[0].Type.Need != funcs.KindNumber || errs[0].Type.Get != funcs.KindString {
+		t.Error("incorrect type error: ", errs[0].Type)
+	}
+	if errs[0].Snippet() != "price + \"USD\"\n      ^" {
+		t.Error("incorrect snippet:\n" + errs[0].Snippet())
+	}
+	if errs[0].Error() != "incorrect type of argument 2 of '+'. Need: number, but get: string at line 1, column 7" {
+		t.Error("incorrect message: " + errs[0].Error())
+	}
+}
+
+func TestCheckTypesPostfix(t *testing.T) {
+	// the per mille operator doesn't convert bools to numbers
+	perMille := funcs.Signature{Params: []funcs.Kind{funcs.KindNumber}, Result: funcs.KindNumber,
+		Infer: func(name string, args []funcs.Kind) (funcs.Kind, error) {
+			if args[0] == funcs.KindBool {
+				return funcs.KindNumber, &funcs.TypeError{What: "argument 1 of '" + name + "'", Need: funcs.KindNumber, Get: funcs.KindBool}
+			}
+			return funcs.KindNumber, nil
+		}}
+	p := NewParser()
+	err := p.RegisterOperator(funcs.Operator{Name: "‰", Fixity: funcs.Postfix, Level: funcs.LevelUnary, Signature: &perMille,
+		Func: func(args ...decimal.Decimal) (decimal.Decimal, error) {
+			return args[0].Shift(-3), nil
+		}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]funcs.Kind{"price": funcs.KindNumber, "vip": funcs.KindBool}
+
+	exp, _ := p.Parse("price‰")
+	if res, errs := exp.CheckTypes(vars); res != funcs.KindNumber || len(errs) != 0 {
+		t.Error("incorrect type of the postfix operator: ", res, errs)
+	}
+	exp, _ = p.Parse("vip‰ + 1!")
+	if _, errs := exp.CheckTypes(vars); len(errs) != 1 || errs[0].Error() != "incorrect type of argument 1 of '‰'. Need: number, but get: bool at line 1, column 4" {
+		t.Error("incorrect errors of the postfix operator: ", errs)
+	}
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/shopspring/decimal"
+)
+
+// CompiledExpression - the parsed expression with a snapshot of the functions
+// which were registered in the Parser at parsing time.
+// It is immutable, so it can be evaluated from several goroutines at once
+type CompiledExpression struct {
+	source    string
+	root      interfaces.Expression
+	ctx       evalContext
+	positions map[interfaces.Expression]token // the tokens of the nodes for the type checker
+}
+
+// evalContext - the context of evaluation, which is private for the compiled expression
+type evalContext struct {
+	functions *funcs.FunctionRegistry
+	settings  funcs.Settings
+}
+
+func (c evalContext) Functions() *funcs.FunctionRegistry {
+	return c.functions
+}
+
+func (c evalContext) Settings() funcs.Settings {
+	return c.settings
+}
+
+// Evaluate - execute expression and return result
+func (e *CompiledExpression) Evaluate(vars map[string]decimal.Decimal) (decimal.Decimal, error) {
+	return e.root.Evaluate(vars, e.ctx)
+}
+
+// EvaluateWithSettings - execute expression with the settings instead of the settings of the Parser
+func (e *CompiledExpression) EvaluateWithSettings(vars map[string]decimal.Decimal, s funcs.Settings) (decimal.Decimal, error) {
+	if err := s.Validate(); err != nil {
+		return decimal.Zero, err
+	}
+	return e.root.Evaluate(vars, evalContext{functions: e.ctx.functions, settings: s})
+}
+
+// EvaluateValue - execute expression with typed variables, like funcs.StringValue("USD"), and return the typed result
+func (e *CompiledExpression) EvaluateValue(vars map[string]funcs.Value) (funcs.Value, error) {
+	return e.root.EvaluateValue(internal.Vars(vars), e.ctx)
+}
+
+// EvaluateValueWithSettings - execute expression with typed variables and the settings instead of the settings of the Parser
+func (e *CompiledExpression) EvaluateValueWithSettings(vars map[string]funcs.Value, s funcs.Settings) (funcs.Value, error) {
+	if err := s.Validate(); err != nil {
+		return funcs.Null, err
+	}
+	return e.root.EvaluateValue(internal.Vars(vars), evalContext{functions: e.ctx.functions, settings: s})
+}
+
+// Settings - the settings which were used by the Parser at parsing time
+func (e *CompiledExpression) Settings() funcs.Settings {
+	return e.ctx.settings
+}
+
+// Root - the root node of the expression tree
+func (e *CompiledExpression) Root() interfaces.Expression {
+	return e.root
+}
+
+// Source - the string which the expression was parsed from
+func (e *CompiledExpression) Source() string {
+	return e.source
+}
+
+func (e *CompiledExpression) GetVarList(vars map[string]interface{}) {
+	e.root.GetVarList(vars)
+}
+
+// String - string representation of expression
+func (e *CompiledExpression) String() string {
+	return e.root.String()
+}
@@ -0,0 +1,213 @@
+package parser
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+func TestCompiledExpressionSnapshot(t *testing.T) {
+	p := NewParser()
+	p.AddFunction(func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.NewFromInt(1), nil
+	}, "f")
+
+	exp1, err := p.Parse("f() + x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.AddFunction(func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.NewFromInt(2), nil
+	}, "f")
+
+	exp2, err := p.Parse("f() + x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars := map[string]decimal.Decimal{"x": decimal.NewFromInt(10)}
+	res, err := exp1.Evaluate(vars)
+	if err != nil || !res.Equal(decimal.NewFromInt(11)) {
+		t.Error("incorrect exp1 result, need: 11, but get: " + res.String())
+	}
+	res, err = exp2.Evaluate(vars)
+	if err != nil || !res.Equal(decimal.NewFromInt(12)) {
+		t.Error("incorrect exp2 result, need: 12, but get: " + res.String())
+	}
+
+	if exp1.Source() != "f() + x" || exp1.String() != "( + ( f (  ) ) x )" {
+		t.Error("incorrect source or string representation: " + exp1.String())
+	}
+}
+
+func TestCompiledExpressionConcurrent(t *testing.T) {
+	p := NewParser()
+	var exps []*CompiledExpression
+	for i := 0; i < 10; i++ {
+		exp, err := p.Parse("x * " + strconv.Itoa(i) + " + abs(-y)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		exps = append(exps, exp)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i, exp := range exps {
+				x := decimal.NewFromInt(int64(g))
+				res, err := exp.Evaluate(map[string]decimal.Decimal{"x": x, "y": decimal.NewFromInt(1)})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if need := x.Mul(decimal.NewFromInt(int64(i))).Add(decimal.NewFromInt(1)); !res.Equal(need) {
+					t.Error("incorrect result, need: " + need.String() + ", but get: " + res.String())
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestParserClone(t *testing.T) {
+	base := NewParser()
+	base.AddFunction(func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return args[0].Mul(decimal.NewFromInt(2)), nil
+	}, "double")
+
+	tenant1 := base.Clone()
+	tenant2 := base.Clone()
+	tenant1.AddFunction(func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return args[0].Mul(decimal.NewFromInt(3)), nil
+	}, "double")
+	if !tenant2.RemoveFunction("double") {
+		t.Error("function was not removed")
+	}
+
+	for _, d := range []struct {
+		p    *Parser
+		need int64
+	}{{base, 4}, {tenant1, 6}} {
+		exp, err := d.p.Parse("double(2)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := exp.Evaluate(nil)
+		if err != nil || !res.Equal(decimal.NewFromInt(d.need)) {
+			t.Error("incorrect result, need: " + strconv.FormatInt(d.need, 10) + ", but get: " + res.String())
+		}
+	}
+
+	if _, err := tenant2.Parse("double(2)"); err == nil {
+		t.Error("removed function was parsed")
+	}
+}
+
+func TestParserConcurrent(t *testing.T) {
+	p := NewParser()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			name := "f" + strconv.Itoa(g)
+			for i := 0; i < 50; i++ {
+				p.AddFunction(func(args ...decimal.Decimal) (decimal.Decimal, error) {
+					return decimal.NewFromInt(int64(g)), nil
+				}, name)
+				exp, err := p.Parse(name + "() + abs(-1)")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				res, err := exp.Evaluate(nil)
+				if err != nil || !res.Equal(decimal.NewFromInt(int64(g+1))) {
+					t.Error("incorrect result: " + res.String())
+				}
+				p.RemoveFunction(name)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestParserSettings(t *testing.T) {
+	type TestData struct {
+		exp string
+		res string
+	}
+
+	p := NewParser()
+	if p.Settings() != funcs.DefaultSettings {
+		t.Error("incorrect default settings")
+	}
+	if err := p.SetSettings(funcs.Settings{Precision: 2, Rounding: funcs.RoundHalfEven}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetSettings(funcs.Settings{Rounding: funcs.RoundingMode(-1)}); err == nil {
+		t.Error("incorrect settings were accepted")
+	}
+	if err := p.SetSettings(funcs.Settings{Precision: -3}); err == nil || err.Error() != "incorrect precision: -3, it must be from 0 to 1000" {
+		t.Error("negative precision was accepted: ", err)
+	}
+	if err := p.SetSettings(funcs.Settings{Precision: funcs.MaxPrecision + 1}); err == nil {
+		t.Error("too big precision was accepted")
+	}
+
+	data := []TestData{
+		{"1 / 8", "0.12"},
+		{"3 / 8", "0.38"},
+		{"2 ^ -3", "0.12"},
+		{"1.05 ^ 2", "1.1025"}, // exact like multiplication
+		{"2 ^ 0.5", "1.41"},
+		{"sqrt(2)", "1.41"},
+		{"exp(1)", "2.72"},
+		{"ln(10)", "2.3"},
+		{"log(2, 10)", "3.32"},
+		{"1 / 3 * 3", "0.99"},
+	}
+
+	for _, d := range data {
+		exp, err := p.Parse(d.exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := exp.Evaluate(nil)
+		if err != nil {
+			t.Error(err)
+		} else if !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect result of " + d.exp + ", need: " + d.res + ", but get: " + res.String())
+		}
+	}
+
+	// the expression keeps the settings of parsing time
+	exp, err := p.Parse("2 / 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := p.Clone()
+	if err := p.SetSettings(funcs.Settings{Precision: 4, Rounding: funcs.RoundDown}); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := exp.Evaluate(nil); !res.Equal(decimal.RequireFromString("0.67")) {
+		t.Error("incorrect result with the settings of parsing time: " + res.String())
+	}
+	if clone.Settings().Precision != 2 {
+		t.Error("incorrect settings of the clone")
+	}
+
+	res, err := exp.EvaluateWithSettings(nil, funcs.Settings{Precision: 3, Rounding: funcs.RoundFloor})
+	if err != nil || !res.Equal(decimal.RequireFromString("0.666")) {
+		t.Error("incorrect result with the evaluation settings: " + res.String())
+	}
+	if _, err := exp.EvaluateWithSettings(nil, funcs.Settings{Rounding: funcs.RoundingMode(-1)}); err == nil {
+		t.Error("incorrect evaluation settings were accepted")
+	}
+}
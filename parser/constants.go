@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/funcs/decmath"
+	"github.com/shopspring/decimal"
+)
+
+// constant - the value of a named constant. It is resolved at parsing time,
+// so the value of a built-in constant is rounded by the settings of the Parser
+type constant func(s funcs.Settings) decimal.Decimal
+
+// builtinConstants - the constants which every new Parser has
+var builtinConstants = map[string]constant{
+	"pi": func(s funcs.Settings) decimal.Decimal {
+		return s.Round(decmath.Pi(s.Precision + decmath.GuardDigits))
+	},
+	"e": func(s funcs.Settings) decimal.Decimal {
+		res, _ := decmath.Exp(decimal.NewFromInt(1), s.Precision+decmath.GuardDigits)
+		return s.Round(res)
+	},
+}
+
+func fixedConstant(value decimal.Decimal) constant {
+	return func(funcs.Settings) decimal.Decimal {
+		return value
+	}
+}
+
+// SetConstant - add the named constant or override the existing one.
+// Its name is replaced by the value in expressions parsed after the call,
+// so it is not a variable and is not reported by GetVarList
+func (p *Parser) SetConstant(name string, value decimal.Decimal) error {
+	if _, keyword := keywords[name]; keyword || !isIdentifier(name) {
+		return errors.New("incorrect constant name: '" + name + "'")
+	}
+	p.updateConstants(func(constants map[string]constant) {
+		constants[name] = fixedConstant(value)
+	})
+	return nil
+}
+
+// RemoveConstant - remove user's or built-in constant, return false if it doesn't exist
+func (p *Parser) RemoveConstant(name string) bool {
+	if _, ok := (*p.constants.Load())[name]; !ok {
+		return false
+	}
+	p.updateConstants(func(constants map[string]constant) {
+		delete(constants, name)
+	})
+	return true
+}
+
+// Constants - the values of the constants with the current settings
+func (p *Parser) Constants() map[string]decimal.Decimal {
+	s := p.Settings()
+	res := make(map[string]decimal.Decimal)
+	for name, c := range *p.constants.Load() {
+		res[name] = c(s)
+	}
+	return res
+}
+
+// updateConstants - apply the change to a copy of the constants and publish it
+func (p *Parser) updateConstants(change func(constants map[string]constant)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old := *p.constants.Load()
+	constants := make(map[string]constant, len(old)+1)
+	for name, c := range old {
+		constants[name] = c
+	}
+	change(constants)
+	p.constants.Store(&constants)
+}
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+func TestConstants(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+		vars   []string
+		res    string
+	}
+
+	p := NewParser()
+	if err := p.SetConstant("taxRate", decimal.RequireFromString("0.2")); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []TestData{
+		{"pi", "3.1415926535897932", nil, "3.1415926535897932"},
+		{"2 * pi * r", "( * ( * 2 3.1415926535897932 ) r )", []string{"r"}, "6.2831853071795864"},
+		{"e", "2.7182818284590452", nil, "2.7182818284590452"},
+		{"price * (1 + taxRate)", "( * price ( + 1 0.2 ) )", []string{"price"}, "1.2"},
+		{"taxRates + pie", "( + taxRates pie )", []string{"pie", "taxRates"}, "2"},
+	}
+
+	vars := map[string]decimal.Decimal{
+		"r":        decimal.NewFromInt(1),
+		"price":    decimal.NewFromInt(1),
+		"taxRates": decimal.NewFromInt(1),
+		"pie":      decimal.NewFromInt(1),
+		// a constant has priority over the variable
+		"taxRate": decimal.NewFromInt(100),
+	}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		if v := GetVarList(exp); !reflect.DeepEqual(v, d.vars) {
+			t.Error("incorrect variables of '"+d.input+"': ", v)
+		}
+		res, err := exp.Evaluate(vars)
+		if err != nil || !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect result of '" + d.input + "', need: " + d.res + ", but get: " + res.String())
+		}
+	}
+
+	if err := p.SetConstant("1x", decimal.Zero); err == nil {
+		t.Error("incorrect constant name was accepted")
+	}
+	if err := p.SetConstant("+", decimal.Zero); err == nil {
+		t.Error("incorrect constant name was accepted")
+	}
+}
+
+func TestConstantsSnapshot(t *testing.T) {
+	p := NewParser()
+	if err := p.SetSettings(funcs.Settings{Precision: 4}); err != nil {
+		t.Fatal(err)
+	}
+	exp, err := p.Parse("pi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp.String() != "3.1416" {
+		t.Error("built-in constant doesn't depend on the settings: " + exp.String())
+	}
+
+	clone := p.Clone()
+	if !p.RemoveConstant("pi") {
+		t.Error("built-in constant was not removed")
+	}
+	if p.RemoveConstant("pi") {
+		t.Error("removed constant was removed again")
+	}
+	if err := p.SetConstant("k", decimal.NewFromInt(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err = p.Parse("pi * k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := GetVarList(exp); !reflect.DeepEqual(v, []string{"pi"}) {
+		t.Error("incorrect variables after the removal of the constant: ", v)
+	}
+
+	// the clone keeps its own constants
+	if _, ok := clone.Constants()["k"]; ok {
+		t.Error("constant of the source Parser was added to the clone")
+	}
+	if c := clone.Constants()["pi"]; c.String() != "3.1416" {
+		t.Error("incorrect constant of the clone: " + c.String())
+	}
+	if _, ok := NewParser().Constants()["pi"]; !ok {
+		t.Error("built-in constant was removed from a new Parser")
+	}
+}
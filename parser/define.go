@@ -0,0 +1,272 @@
+package parser
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+)
+
+// definition - the source of a function defined by DefineFunction and the names of the functions it calls
+type definition struct {
+	source string
+	calls  []string
+	seq    int // the order of definitions
+}
+
+// DefineFunction - define the function by the text 'name(a, b) = exp', like 'margin(p, c) = (p - c) / p',
+// or replace the function defined by DefineFunction before. A function registered by Go code can't be replaced. The expression can use the parameters, constants and functions only.
+// It is compiled with a snapshot of the functions, but functions defined by DefineFunction which call
+// the redefined one are compiled again, so they use the new definition. A recursive call is an error.
+// Expressions parsed before the call keep the old definition
+func (p *Parser) DefineFunction(src string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the definitions are compiled by the scratch Parser, so an error doesn't change the Parser
+	scratch := p.cloneState()
+	defs := make(map[string]definition, len(p.definitions)+1)
+	for name, d := range p.definitions {
+		defs[name] = d
+	}
+
+	name, err := scratch.define(src, defs, p.nextSeq())
+	if err != nil {
+		return err
+	}
+	changed := map[string]struct{}{name: {}}
+	for _, dep := range sortDefinitions(defs) {
+		if _, ok := changed[dep]; ok {
+			continue
+		}
+		for _, call := range defs[dep].calls {
+			if _, ok := changed[call]; ok {
+				if _, err := scratch.define(defs[dep].source, defs, defs[dep].seq); err != nil {
+					return errors.New("incorrect definition of '" + dep + "' after the change of '" + call + "': " + err.Error())
+				}
+				changed[dep] = struct{}{}
+				break
+			}
+		}
+	}
+
+	for changedName := range changed {
+		d, _ := scratch.functions.Describe(changedName)
+		if err := p.functions.RegisterFunction(d); err != nil {
+			return err
+		}
+	}
+	p.definitions = defs
+	return nil
+}
+
+// Definitions - the sources of the functions defined by DefineFunction, a function follows the functions it calls.
+// Pass them to DefineFunction of another Parser in this order to restore the functions
+func (p *Parser) Definitions() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var res []string
+	for _, name := range sortDefinitions(p.definitions) {
+		res = append(res, p.definitions[name].source)
+	}
+	return res
+}
+
+// define - compile the definition and register the function, add it to defs
+func (p *Parser) define(src string, defs map[string]definition, seq int) (string, error) {
+	ctx := evalContext{functions: p.functions.Clone(), settings: p.Settings()}
+	opts := parseOptions{constants: *p.constants.Load(), implicitMul: p.implicitMul.Load()}
+	ep, name, params, body, err := parseDefinitionStr(src, ctx, opts)
+	if err == nil {
+		err = checkName(ctx.functions, name, defs)
+	}
+	if err == nil {
+		err = checkRecursion(ep, name.text, defs)
+	}
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.locate(src)
+		}
+		return "", err
+	}
+
+	exp := &CompiledExpression{source: src, root: body, ctx: ctx, positions: ep.positions}
+	call := func(s funcs.Settings, args ...funcs.Value) (funcs.Value, error) {
+		if err := funcs.CheckArgsCount("function '"+name.text+"'", len(params), args); err != nil {
+			return funcs.Null, err
+		}
+		vars := make(map[string]funcs.Value, len(params))
+		for i, param := range params {
+			vars[param] = args[i]
+		}
+		return exp.EvaluateValueWithSettings(vars, s)
+	}
+	// the parameters can have any type, so the result type is inferred without them
+	kinds := make(map[string]funcs.Kind, len(params))
+	for _, param := range params {
+		kinds[param] = funcs.KindAny
+	}
+	res, _ := exp.CheckTypes(kinds)
+	d := funcs.Descriptor{
+		Name:        name.text,
+		Value:       call,
+		MinArgs:     len(params),
+		MaxArgs:     len(params),
+		Description: strings.TrimSpace(src),
+		Pure:        true,
+		Signature:   &funcs.Signature{Params: []funcs.Kind{funcs.KindAny}, Result: res},
+	}
+	if err := p.functions.RegisterFunction(d); err != nil {
+		return "", err
+	}
+
+	var calls []string
+	for _, t := range ep.calls {
+		calls = append(calls, t.text)
+	}
+	defs[name.text] = definition{source: strings.TrimSpace(src), calls: calls, seq: seq}
+	return name.text, nil
+}
+
+// checkName - checks that the function is new or defined by DefineFunction, so a definition never replaces
+// a function registered by Go code, like 'sqrt' or 'if' which is called by '?:'
+func checkName(functions *funcs.FunctionRegistry, name token, defs map[string]definition) error {
+	d, ok := functions.Describe(name.text)
+	if !ok {
+		return nil
+	}
+	// Go code could register the function again after its definition
+	if def, defined := defs[name.text]; defined && d.Description == def.source {
+		return nil
+	}
+	return &ParseError{Offset: name.pos, Found: name.text, Msg: "function '" + name.text + "' is already registered"}
+}
+
+// checkRecursion - checks that no function called by the definition calls the defined function
+func checkRecursion(ep *exprParser, name string, defs map[string]definition) error {
+	for _, call := range ep.calls {
+		visited := make(map[string]struct{})
+		if callsFunction(call.text, name, defs, visited) {
+			return &ParseError{Offset: call.pos, Found: call.text,
+				Msg: "recursive call of function '" + name + "' through '" + call.text + "'"}
+		}
+	}
+	return nil
+}
+
+// callsFunction - checks that the defined function from calls the function target directly or indirectly
+func callsFunction(from, target string, defs map[string]definition, visited map[string]struct{}) bool {
+	if _, ok := visited[from]; ok {
+		return false
+	}
+	visited[from] = struct{}{}
+	for _, call := range defs[from].calls {
+		if call == target || callsFunction(call, target, defs, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortDefinitions - the names of the definitions, a function follows the functions it calls,
+// otherwise the order of definitions is kept
+func sortDefinitions(defs map[string]definition) []string {
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return defs[names[i]].seq < defs[names[j]].seq
+	})
+
+	res := make([]string, 0, len(defs))
+	added := make(map[string]struct{}, len(defs))
+	var add func(name string)
+	add = func(name string) {
+		if _, ok := added[name]; ok {
+			return
+		}
+		added[name] = struct{}{}
+		for _, call := range defs[name].calls {
+			if _, ok := defs[call]; ok {
+				add(call)
+			}
+		}
+		res = append(res, name)
+	}
+	for _, name := range names {
+		add(name)
+	}
+	return res
+}
+
+// nextSeq - the order number of a new definition, the caller holds the lock
+func (p *Parser) nextSeq() int {
+	p.seq++
+	return p.seq
+}
+
+// parseDefinition - parse 'name(a, b) = exp'. The expression can use the parameters only,
+// the functions called by it are collected in ep.calls
+func (ep *exprParser) parseDefinition() (name token, params []string, body interfaces.Expression, err error) {
+	name = ep.next()
+	if name.kind != tokIdent {
+		return name, nil, nil, unexpected(name, "function name")
+	}
+	if err := ep.expect(tokLParen, "("); err != nil {
+		return name, nil, nil, err
+	}
+
+	ep.params = make(map[string]struct{})
+	if ep.peek().kind == tokRParen {
+		ep.next()
+	} else {
+		for done := false; !done; {
+			t := ep.next()
+			if t.kind != tokIdent {
+				return name, nil, nil, unexpected(t, "parameter name")
+			}
+			if _, ok := ep.params[t.text]; ok {
+				return name, nil, nil, &ParseError{Offset: t.pos, Found: t.text, Msg: "duplicate parameter '" + t.text + "'"}
+			}
+			if _, ok := ep.opts.constants[t.text]; ok {
+				return name, nil, nil, &ParseError{Offset: t.pos, Found: t.text, Msg: "parameter '" + t.text + "' is a constant"}
+			}
+			ep.params[t.text] = struct{}{}
+			params = append(params, t.text)
+
+			switch t := ep.next(); t.kind {
+			case tokComma:
+			case tokRParen:
+				done = true
+			default:
+				return name, nil, nil, unexpected(t, "','", "')'")
+			}
+		}
+	}
+	if err := ep.expect(tokAssign, "="); err != nil {
+		return name, nil, nil, err
+	}
+
+	ep.defining = name.text
+	if body, err = ep.parseExpression(bpLowest); err != nil {
+		return name, nil, nil, err
+	}
+	if t := ep.peek(); t.kind != tokEOF {
+		return name, nil, nil, unexpected(t, "operator")
+	}
+	return name, params, body, nil
+}
+
+// parseDefinitionStr - tokenize and parse the function definition
+func parseDefinitionStr(str string, ctx interfaces.Context, opts parseOptions) (*exprParser, token, []string, interfaces.Expression, error) {
+	tokens, err := newLexer(str, operatorSymbols(ctx)).tokenize()
+	if err != nil {
+		return nil, token{}, nil, nil, err
+	}
+	ep := &exprParser{ctx: ctx, opts: opts, tokens: tokens}
+	name, params, body, err := ep.parseDefinition()
+	return ep, name, params, body, err
+}
@@ -0,0 +1,224 @@
+package parser
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/shopspring/decimal"
+)
+
+func TestDefineFunction(t *testing.T) {
+	p := NewParser()
+	for _, src := range []string{
+		"margin(p, c) = (p - c) / p",
+		"markup(p, c) = margin(p, c) * 100",
+		"circle(r) = pi * r^2",
+		"answer() = 42",
+	} {
+		if err := p.DefineFunction(src); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type TestData struct {
+		input string
+		vars  []string
+		res   string
+	}
+	data := []TestData{
+		{"margin(price, cost)", []string{"cost", "price"}, "0.2"},
+		// the parameters are not the variables of the caller
+		{"margin(10, 5) + p", []string{"p"}, "1.5"},
+		{"markup(price, 6)", []string{"price"}, "40"},
+		{"circle(1)", nil, "3.1415926535897932"},
+		{"answer() / 2", nil, "21"},
+	}
+	vars := map[string]decimal.Decimal{"price": decimal.NewFromInt(10), "cost": decimal.NewFromInt(8), "p": decimal.NewFromInt(1)}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if v := GetVarList(exp); !reflect.DeepEqual(v, d.vars) {
+			t.Error("incorrect variables of '"+d.input+"': ", v)
+		}
+		res, err := exp.Evaluate(vars)
+		if err != nil || !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+
+	// the settings of the evaluation are used by the body
+	exp, _ := p.Parse("margin(3, 2)")
+	res, _ := exp.EvaluateWithSettings(nil, funcs.Settings{Precision: 2})
+	if !res.Equal(decimal.RequireFromString("0.33")) {
+		t.Error("incorrect result with the settings: " + res.String())
+	}
+
+	// the parameters and the result can have any type
+	if err := p.DefineFunction(`symbol(code) = if(code == "USD", "$", code)`); err != nil {
+		t.Fatal(err)
+	}
+	exp, _ = p.Parse(`concat(symbol(currency), price)`)
+	val, err := exp.EvaluateValue(map[string]funcs.Value{"currency": funcs.StringValue("USD"), "price": funcs.NumberValue(decimal.NewFromInt(5))})
+	if err != nil || !val.Equal(funcs.StringValue("$5")) {
+		t.Error("incorrect typed result: ", val, err)
+	}
+
+	if d, ok := p.Functions().Describe("margin"); !ok || d.MinArgs != 2 || d.MaxArgs != 2 || d.Description != "margin(p, c) = (p - c) / p" {
+		t.Error("incorrect descriptor of the defined function: ", d)
+	}
+	if _, err := p.Parse("margin(1)"); err == nil {
+		t.Error("call with incorrect count of arguments was parsed")
+	}
+}
+
+func TestDefineFunctionErrors(t *testing.T) {
+	p := NewParser()
+	if err := p.DefineFunction("f(x) = x + 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.DefineFunction("g(x) = f(x) * 2"); err != nil {
+		t.Fatal(err)
+	}
+	p.AddFunction(func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.Zero, nil
+	}, "foo")
+
+	type TestData struct {
+		input string
+		err   string
+	}
+	data := []TestData{
+		{"h(x) = x + y", "unknown variable 'y' in function 'h' at line 1, column 12"},
+		{"h(x) = h(x - 1)", "recursive call of function 'h' at line 1, column 8"},
+		{"f(x) = g(x) + 1", "recursive call of function 'f' through 'g' at line 1, column 8"},
+		{"h(x, x) = x", "duplicate parameter 'x' at line 1, column 6"},
+		{"h(pi) = pi", "parameter 'pi' is a constant at line 1, column 3"},
+		{"h(x) = ", "unexpected end of expression at line 1, column 8: expected operand"},
+		{"h(x) x", "unexpected 'x' at line 1, column 6: expected '='"},
+		{"h(1) = 1", "unexpected '1' at line 1, column 3: expected parameter name"},
+		{"1 + 2", "unexpected '1' at line 1, column 1: expected function name"},
+		{"h(x) = x; 1", "unknown symbol ';' at line 1, column 9"},
+		// built-in and Go functions can't be replaced
+		{"sqrt(x) = x", "function 'sqrt' is already registered at line 1, column 1"},
+		{"if(c, a, b) = a", "function 'if' is already registered at line 1, column 1"},
+		{"foo(x) = x", "function 'foo' is already registered at line 1, column 1"},
+		// g calls f with one argument
+		{"f(x, y) = x + y", "incorrect definition of 'g' after the change of 'f': " +
+			"incorrect count of args for 'f' function. Need: 2, but get: 1 at line 1, column 8"},
+	}
+	for _, d := range data {
+		err := p.DefineFunction(d.input)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+
+	// the failed definitions didn't change the Parser
+	exp, err := p.Parse("g(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := exp.Evaluate(nil); err != nil || !res.Equal(decimal.NewFromInt(4)) {
+		t.Error("incorrect result after the failed definitions: ", res, err)
+	}
+	if !reflect.DeepEqual(p.Definitions(), []string{"f(x) = x + 1", "g(x) = f(x) * 2"}) {
+		t.Error("incorrect definitions: ", p.Definitions())
+	}
+	exp, _ = p.Parse("true ? sqrt(4) : 0")
+	if res, err := exp.Evaluate(nil); err != nil || !res.Equal(decimal.NewFromInt(2)) {
+		t.Error("incorrect result of the built-in functions: ", res, err)
+	}
+
+	// a defined function which is replaced by Go code is not defined by the text anymore
+	if err := p.RegisterFunction(funcs.Descriptor{Name: "f", Func: funcs.FuncType(func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return args[0], nil
+	}), MinArgs: 1, MaxArgs: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.DefineFunction("f(x) = x + 2"); err == nil || err.Error() != "function 'f' is already registered at line 1, column 1" {
+		t.Error("incorrect error for the replaced definition: ", err)
+	}
+}
+
+func TestRedefineFunction(t *testing.T) {
+	p := NewParser()
+	for _, src := range []string{"tax(x) = x * 0.2", "total(x) = x + tax(x)", "double(x) = total(x) * 2"} {
+		if err := p.DefineFunction(src); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, _ := p.Parse("double(100)")
+
+	// the functions which call 'tax' are compiled again
+	if err := p.DefineFunction("tax(x) = x * rate()"); err == nil {
+		t.Fatal("definition with the unknown function was accepted")
+	}
+	if err := p.DefineFunction("rate() = 0.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.DefineFunction("tax(x) = x * rate()"); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := p.Parse("double(100)")
+	if res, _ := after.Evaluate(nil); !res.Equal(decimal.NewFromInt(300)) {
+		t.Error("incorrect result after the redefinition: " + res.String())
+	}
+	// the expression keeps the functions of parsing time
+	if res, _ := before.Evaluate(nil); !res.Equal(decimal.NewFromInt(240)) {
+		t.Error("incorrect result of the old expression: " + res.String())
+	}
+
+	// a function follows the functions it calls, so the definitions can be restored in this order
+	defs := p.Definitions()
+	need := []string{"rate() = 0.5", "tax(x) = x * rate()", "total(x) = x + tax(x)", "double(x) = total(x) * 2"}
+	if !reflect.DeepEqual(defs, need) {
+		t.Error("incorrect definitions: ", defs)
+	}
+	restored := NewParser()
+	for _, src := range defs {
+		if err := restored.DefineFunction(src); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exp, _ := restored.Parse("double(100)")
+	if res, _ := exp.Evaluate(nil); !res.Equal(decimal.NewFromInt(300)) {
+		t.Error("incorrect result of the restored functions: " + res.String())
+	}
+
+	clone := p.Clone()
+	if !p.RemoveFunction("double") {
+		t.Error("defined function was not removed")
+	}
+	if len(p.Definitions()) != 3 || len(clone.Definitions()) != 4 {
+		t.Error("incorrect definitions after the removal: ", p.Definitions(), clone.Definitions())
+	}
+}
+
+func TestDefineFunctionConcurrent(t *testing.T) {
+	p := NewParser()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := p.DefineFunction("f(x) = x * 2"); err != nil {
+					t.Error(err)
+					return
+				}
+				if exp, err := p.Parse("f(2)"); err != nil {
+					t.Error(err)
+				} else if res, _ := exp.Evaluate(nil); !res.Equal(decimal.NewFromInt(4)) {
+					t.Error("incorrect result: " + res.String())
+				}
+				_ = p.Definitions()
+			}
+		}()
+	}
+	wg.Wait()
+}
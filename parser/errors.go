@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseError - describes the place where the expression is broken.
+// Offset, Line and Column point into the original string passed to Parse
+type ParseError struct {
+	Source   string   // the parsed expression
+	Offset   int      // byte offset of the error in Source
+	Line     int      // 1-based line number
+	Column   int      // 1-based column number, counted in runes
+	Found    string   // the text of the unexpected token, empty at the end of the expression
+	Expected []string // descriptions of the tokens which would be accepted at this place
+	Msg      string   // the reason, if the error is not about an unexpected token
+}
+
+// Error - error message with the position and the expected set
+func (e *ParseError) Error() string {
+	str := e.Msg
+	if str == "" {
+		str = "unexpected " + e.found()
+	}
+	str += " at line " + strconv.Itoa(e.Line) + ", column " + strconv.Itoa(e.Column)
+	if len(e.Expected) > 0 {
+		str += ": expected " + strings.Join(e.Expected, " or ")
+	}
+	return str
+}
+
+func (e *ParseError) found() string {
+	switch e.Found {
+	case "":
+		return "end of expression"
+	case "\n":
+		return "line break"
+	}
+	return "'" + e.Found + "'"
+}
+
+// Snippet - the source line of the error underlined with a caret, like:
+//
+//	2 * (x + y
+//	          ^
+func (e *ParseError) Snippet() string {
+	lineStart := strings.LastIndexByte(e.Source[:e.Offset], '\n') + 1
+	lineEnd := strings.IndexByte(e.Source[e.Offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(e.Source)
+	} else {
+		lineEnd += e.Offset
+	}
+	line := strings.TrimRight(e.Source[lineStart:lineEnd], "\r")
+
+	var pad strings.Builder
+	for _, r := range e.Source[lineStart:e.Offset] {
+		// keep tabs, so the caret stays aligned with the line above
+		if r == '\t' {
+			pad.WriteRune('\t')
+		} else {
+			pad.WriteRune(' ')
+		}
+	}
+	width := utf8.RuneCountInString(e.Found)
+	if width < 1 {
+		width = 1
+	}
+	return line + "\n" + pad.String() + "^" + strings.Repeat("~", width-1)
+}
+
+// locate - set the line and the column of the error in the source string
+func (e *ParseError) locate(src string) {
+	e.Source = src
+	if e.Offset > len(src) {
+		e.Offset = len(src)
+	}
+	e.Line = strings.Count(src[:e.Offset], "\n") + 1
+	e.Column = utf8.RuneCountInString(src[strings.LastIndexByte(src[:e.Offset], '\n')+1:e.Offset]) + 1
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	type TestData struct {
+		input  string
+		line   int
+		column int
+		found  string
+		snip   string
+	}
+
+	data := []TestData{
+		{"2 * (x + y", 1, 11, "", "2 * (x + y\n          ^"},
+		{"  1 +  * 3", 1, 8, "*", "  1 +  * 3\n       ^"},
+		{"доход + )", 1, 9, ")", "доход + )\n        ^"},
+		{"a +\n\tb + foo(1)", 2, 6, "foo", "\tb + foo(1)\n\t    ^~~"},
+		{"1 # 2", 1, 3, "#", "1 # 2\n  ^"},
+		{"price  qty", 1, 8, "qty", "price  qty\n       ^~~"},
+		{"2 3", 1, 3, "3", "2 3\n  ^"},
+		{"x < = 1", 1, 5, "=", "x < = 1\n    ^"},
+	}
+
+	p := NewParser()
+	for _, d := range data {
+		_, err := p.Parse(d.input)
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Error("ParseError was not returned for '" + d.input + "'")
+			continue
+		}
+		if perr.Line != d.line || perr.Column != d.column {
+			t.Error("incorrect position for '" + d.input + "': " +
+				strconv.Itoa(perr.Line) + ":" + strconv.Itoa(perr.Column))
+		}
+		if perr.Found != d.found {
+			t.Error("incorrect found token for '" + d.input + "': " + perr.Found)
+		}
+		if perr.Snippet() != d.snip {
+			t.Error("incorrect snippet for '" + d.input + "':\n" + perr.Snippet())
+		}
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	p := NewParser()
+	_, err := p.Parse("sqrt(1 (2)")
+	if err == nil || err.Error() != "missing operator between operands at line 1, column 8" {
+		t.Error("incorrect error message: ", err)
+	}
+	_, err = p.Parse("sqrt(1 : 2)")
+	if err == nil || err.Error() != "unexpected ':' at line 1, column 8: expected ',' or ')'" {
+		t.Error("incorrect error message: ", err)
+	}
+	_, err = p.Parse("(1")
+	if err == nil || err.Error() != "unexpected end of expression at line 1, column 3: expected ')'" {
+		t.Error("incorrect error message: ", err)
+	}
+	_, err = p.Parse("Foo(x)")
+	if err == nil || err.Error() != "function 'Foo' is not supported at line 1, column 1" {
+		t.Error("incorrect error message: ", err)
+	}
+}
@@ -0,0 +1,242 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/arconomy/go-math-expression-parser/internal"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOperator
+	tokComma
+	tokLParen
+	tokRParen
+	tokQuestion
+	tokColon
+	tokAssign    // '=' of a script statement
+	tokSeparator // ';' or a line break between script statements
+	tokLiteral   // a quoted string, true, false or null
+	tokLBracket  // '[' of a list literal or an index
+	tokRBracket
+	tokArrow // '->' of a lambda
+	tokDot   // '.' of a member access
+)
+
+// keywords - the names which are literals, they can't be variables
+var keywords = map[string]struct{}{"true": {}, "false": {}, "null": {}}
+
+// token - a single lexeme of the expression and its byte offset in the source string
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lexer - splits the expression string into tokens.
+// Operators are matched greedily against the set of known operator symbols,
+// so multi-character operators are recognized as a single token
+type lexer struct {
+	src    string
+	pos    int
+	ops    []string
+	script bool // a line break outside of parentheses separates statements
+	depth  int  // of parentheses and brackets
+}
+
+func newLexer(src string, ops []string) *lexer {
+	sorted := make([]string, len(ops))
+	copy(sorted, ops)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+	return &lexer{src: src, ops: sorted}
+}
+
+// tokenize - return all tokens of the source string, the last one is always tokEOF
+func (l *lexer) tokenize() ([]token, error) {
+	var tokens []token
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		if t.kind == tokEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpaces()
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+	start := l.pos
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	op := l.operator()
+
+	switch {
+	case r == '(':
+		l.pos += size
+		l.depth++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case r == ')':
+		l.pos += size
+		l.depth--
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case r == '[':
+		l.pos += size
+		l.depth++
+		return token{kind: tokLBracket, text: "[", pos: start}, nil
+	case r == ']':
+		l.pos += size
+		l.depth--
+		return token{kind: tokRBracket, text: "]", pos: start}, nil
+	case l.script && (r == ';' || r == '\n'):
+		l.pos += size
+		return token{kind: tokSeparator, text: string(r), pos: start}, nil
+	case r == ',':
+		l.pos += size
+		return token{kind: tokComma, text: ",", pos: start}, nil
+	case strings.HasPrefix(l.src[l.pos:], "->"):
+		// an operator name can't contain the arrow, so it is not the operator '-'
+		l.pos += 2
+		return token{kind: tokArrow, text: "->", pos: start}, nil
+	case isDigit(r) || (r == '.' && isDigit(l.peekRune(size))):
+		return l.number(), nil
+	case r == '"':
+		return l.string()
+	case isIdentStart(r):
+		// an operator which starts with a letter, like 'max|', wins over the shorter name
+		if t := l.ident(); len(t.text) >= len(op) {
+			if _, ok := keywords[t.text]; ok {
+				t.kind = tokLiteral
+			}
+			return t, nil
+		}
+		l.pos = start
+	}
+
+	if op != "" {
+		l.pos += len(op)
+		return token{kind: tokOperator, text: op, pos: start}, nil
+	}
+	switch r {
+	case '.':
+		l.pos += size
+		return token{kind: tokDot, text: ".", pos: start}, nil
+	case '?':
+		l.pos += size
+		return token{kind: tokQuestion, text: "?", pos: start}, nil
+	case ':':
+		l.pos += size
+		return token{kind: tokColon, text: ":", pos: start}, nil
+	case '=':
+		l.pos += size
+		return token{kind: tokAssign, text: "=", pos: start}, nil
+	}
+	return token{}, &ParseError{Offset: start, Found: string(r), Msg: "unknown symbol '" + string(r) + "'"}
+}
+
+// operator - return the longest operator symbol at the current position, "" if there is no one
+func (l *lexer) operator() string {
+	for _, op := range l.ops {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func (l *lexer) skipSpaces() {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !unicode.IsSpace(r) || r == '\n' && l.script && l.depth <= 0 {
+			return
+		}
+		l.pos += size
+	}
+}
+
+// peekRune - return the rune placed after the current one
+func (l *lexer) peekRune(offset int) rune {
+	if l.pos+offset >= len(l.src) {
+		return utf8.RuneError
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos+offset:])
+	return r
+}
+
+// number - scan the numeric literal by the grammar of internal.ScanNumber
+func (l *lexer) number() token {
+	start := l.pos
+	l.pos += internal.ScanNumber(l.src[l.pos:])
+	return token{kind: tokNumber, text: l.src[start:l.pos], pos: start}
+}
+
+// string - scan the quoted string with Go escapes, like "a\tb"
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case '"':
+			l.pos++
+			text := l.src[start:l.pos]
+			if _, err := strconv.Unquote(text); err != nil {
+				return token{}, &ParseError{Offset: start, Found: text, Msg: "incorrect string " + text}
+			}
+			return token{kind: tokLiteral, text: text, pos: start}, nil
+		}
+	}
+	return token{}, &ParseError{Offset: start, Found: l.src[start:], Msg: "unterminated string"}
+}
+
+func (l *lexer) ident() token {
+	start := l.pos
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !isIdentPart(r) {
+			break
+		}
+		l.pos += size
+	}
+	return token{kind: tokIdent, text: l.src[start:l.pos], pos: start}
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+// isIdentifier - checks that the whole string is a name (of a function or a variable),
+// not an operator symbol
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if i == 0 && !isIdentStart(r) || !isIdentPart(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestLexer(t *testing.T) {
+	type TestData struct {
+		input string
+		kinds []tokenKind
+		texts []string
+	}
+
+	data := []TestData{
+		{"", []tokenKind{tokEOF}, []string{""}},
+		{"1.5+x", []tokenKind{tokNumber, tokOperator, tokIdent, tokEOF}, []string{"1.5", "+", "x", ""}},
+		{"f(a,.5)", []tokenKind{tokIdent, tokLParen, tokIdent, tokComma, tokNumber, tokRParen, tokEOF}, []string{"f", "(", "a", ",", ".5", ")", ""}},
+		{"доход_1**2", []tokenKind{tokIdent, tokOperator, tokNumber, tokEOF}, []string{"доход_1", "**", "2", ""}},
+		{"a*-b", []tokenKind{tokIdent, tokOperator, tokOperator, tokIdent, tokEOF}, []string{"a", "*", "-", "b", ""}},
+		{`"a,\"b"+true`, []tokenKind{tokLiteral, tokOperator, tokLiteral, tokEOF}, []string{`"a,\"b"`, "+", "true", ""}},
+		{"nullable", []tokenKind{tokIdent, tokEOF}, []string{"nullable", ""}},
+		{"(a,b)->a-->b", []tokenKind{tokLParen, tokIdent, tokComma, tokIdent, tokRParen, tokArrow, tokIdent, tokOperator, tokArrow, tokIdent, tokEOF},
+			[]string{"(", "a", ",", "b", ")", "->", "a", "-", "->", "b", ""}},
+		{"order.total+.5", []tokenKind{tokIdent, tokDot, tokIdent, tokOperator, tokNumber, tokEOF}, []string{"order", ".", "total", "+", ".5", ""}},
+		{"xs[-1]", []tokenKind{tokIdent, tokLBracket, tokOperator, tokNumber, tokRBracket, tokEOF}, []string{"xs", "[", "-", "1", "]", ""}},
+	}
+
+	for _, d := range data {
+		tokens, err := newLexer(d.input, []string{"+", "-", "*", "**"}).tokenize()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if len(tokens) != len(d.kinds) {
+			t.Error("incorrect count of tokens for '" + d.input + "'")
+			continue
+		}
+		for i, tok := range tokens {
+			if tok.kind != d.kinds[i] || tok.text != d.texts[i] {
+				t.Error("incorrect token '" + tok.text + "' for '" + d.input + "', need: '" + d.texts[i] + "'")
+			}
+		}
+	}
+
+	tokens, _ := newLexer("ab + 12", []string{"+"}).tokenize()
+	if tokens[1].pos != 3 || tokens[2].pos != 5 {
+		t.Error("incorrect token positions")
+	}
+
+	if _, err := newLexer("a # b", []string{"+"}).tokenize(); err == nil {
+		t.Error("unknown symbol was not handled")
+	}
+}
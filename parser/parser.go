@@ -4,239 +4,212 @@ import (
 	"errors"
 	"sort"
 	"strconv"
-	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/arconomy/go-math-expression-parser/funcs"
 	dfuncs "github.com/arconomy/go-math-expression-parser/funcs/basic"
-	"github.com/arconomy/go-math-expression-parser/funcs/userfunc"
 	"github.com/arconomy/go-math-expression-parser/interfaces"
-	"github.com/arconomy/go-math-expression-parser/internal"
-	"github.com/shopspring/decimal"
 )
 
-// Parser - context structure, which contains user-defined function
+// Parser - context structure, which contains user-defined functions and constants.
+// It is safe for concurrent use
 type Parser struct {
-	Operators  [funcs.LevelsOfPriorities]map[string]funcs.FuncType
-	Expression interfaces.Expression
+	functions   *funcs.FunctionRegistry
+	settings    atomic.Pointer[funcs.Settings]
+	mu          sync.Mutex // serializes writers of constants and guards definitions
+	constants   atomic.Pointer[map[string]constant]
+	implicitMul atomic.Bool
+	definitions map[string]definition // functions defined by DefineFunction
+	seq         int
 }
 
 // NewParser - create a Parser object with default set of operators and functions
 func NewParser() *Parser {
-	p := new(Parser)
-
-	for i := range p.Operators {
-		p.Operators[i] = make(map[string]funcs.FuncType)
-		for key, f := range dfuncs.DefaultOperators[i] {
-			p.Operators[i][key] = f
-		}
-	}
+	return NewParserWithFunctions(dfuncs.NewRegistry())
+}
 
+// NewParserWithFunctions - create a Parser object which uses the registry of functions,
+// funcs.DefaultSettings and the built-in constants pi and e
+func NewParserWithFunctions(functions *funcs.FunctionRegistry) *Parser {
+	p := &Parser{functions: functions}
+	s := funcs.DefaultSettings
+	p.settings.Store(&s)
+	// the map is never changed, updates replace it by a copy
+	constants := builtinConstants
+	p.constants.Store(&constants)
 	return p
 }
 
-// AddFunction - add user's function and it string representation
-func (p *Parser) AddFunction(f funcs.FuncType, s string) {
-	p.Operators[0][s] = f
+// Clone - create an independent Parser with the same functions, settings and constants
+func (p *Parser) Clone() *Parser {
+	c := p.cloneState()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c.definitions = make(map[string]definition, len(p.definitions))
+	for name, d := range p.definitions {
+		c.definitions[name] = d
+	}
+	c.seq = p.seq
+	return c
 }
 
-func (p *Parser) GetFunctions() [funcs.LevelsOfPriorities]map[string]funcs.FuncType {
-	return p.Operators
+// cloneState - clone the functions, the settings and the options without definitions
+func (p *Parser) cloneState() *Parser {
+	c := NewParserWithFunctions(p.functions.Clone())
+	c.settings.Store(p.settings.Load())
+	c.constants.Store(p.constants.Load())
+	c.implicitMul.Store(p.implicitMul.Load())
+	return c
 }
 
-// String - string representation of expression
-func (p *Parser) String() string {
-	return p.Expression.String()
+// SetImplicitMultiplication - enable or disable implicit multiplication in expressions parsed after the call.
+// If it is enabled, a number followed by a name or '(' ('2x', '3(a + b)'), a name which is not a function
+// followed by '(' ('x(a + b)'), and ')' followed by a name or '(' ('(a + b)(c - d)') are multiplied.
+// The implicit multiplication has the same precedence as '*', so '1 / 2x' is '(1 / 2) * x'.
+// It is disabled by default
+func (p *Parser) SetImplicitMultiplication(enabled bool) {
+	p.implicitMul.Store(enabled)
 }
 
-// Parse - parsing a string format math expression, return Exp tree
-func (p *Parser) Parse(str string) (interfaces.Expression, error) {
-	if indx, ok := internal.ParenthesisIsCorrect(str); !ok {
-		return nil, errors.New("incorrect parenthesis at " + strconv.Itoa(indx) + " position")
-	}
-	str = internal.PrepareString(str)
-	//fmt.Println("Remove spaces: '" + str + "'")
-	res, err := p.parseStr([]rune(str))
-	if err != nil {
-		return nil, err
-	}
-	p.Expression = res
-	return res, nil
+// ImplicitMultiplication - checks that the implicit multiplication is enabled
+func (p *Parser) ImplicitMultiplication() bool {
+	return p.implicitMul.Load()
 }
 
-// Evaluate - execute expression and return result
-func (p *Parser) Evaluate(vars map[string]decimal.Decimal) (decimal.Decimal, error) {
-	result, err := p.Expression.Evaluate(vars, p)
-	return result, err
+// Settings - the precision and the rounding mode of inexact results, like division or square root
+func (p *Parser) Settings() funcs.Settings {
+	return *p.settings.Load()
 }
 
-func isValidBinaryOperatorContext(str []rune, operatorIndex int) bool {
-	if operatorIndex <= 0 {
-		return false
-	}
-	prevChar := str[operatorIndex-1]
-	switch prevChar {
-	case '+', '-', '*', '/', '%', '^', '(':
-		return false
-	default:
-		return true
+// SetSettings - change the precision and the rounding mode of expressions parsed after the call.
+// The angle mode is kept, s.Angle is ignored: it is changed by SetAngleMode only
+func (p *Parser) SetSettings(s funcs.Settings) error {
+	for {
+		old := p.settings.Load()
+		s.Angle = old.Angle
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		if p.settings.CompareAndSwap(old, &s) {
+			return nil
+		}
 	}
 }
 
-func (p *Parser) parseFunc(str []rune) (f interfaces.Function, isFunc bool, err error) {
-	ind := strings.IndexRune(string(str), '(')
-	var args [][]rune
-	if ind <= 0 {
-		return &userfunc.Func{}, false, nil
-	}
-	f = new(userfunc.Func)
-	f.SetOperation(string(str[:ind]))
-	if _, ok := p.Operators[0][f.GetOperation()]; !ok {
-		return &userfunc.Func{}, false, errors.New("function '" + f.GetOperation() + "' is not supported")
+// SetAngleMode - change the units of angles of trigonometric functions in expressions parsed after the call,
+// other settings are kept
+func (p *Parser) SetAngleMode(m funcs.AngleMode) error {
+	for {
+		old := p.settings.Load()
+		s := *old
+		s.Angle = m
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		if p.settings.CompareAndSwap(old, &s) {
+			return nil
+		}
 	}
+}
 
-	level := 0
-
-	start := ind + 1
+// AddFunction - add user's function and it string representation
+func (p *Parser) AddFunction(f funcs.FuncType, s string) {
+	p.functions.Register(0, s, f)
+}
 
-	for i := start; i <= len(str)-1; i++ {
-		c := str[i]
-		switch c {
-		case '(':
-			level++
-			//end--
-			continue
+// RegisterFunction - add user's function with its metadata, so calls of the function
+// with incorrect count of arguments are rejected by Parse
+func (p *Parser) RegisterFunction(d funcs.Descriptor) error {
+	return p.functions.RegisterFunction(d)
+}
 
-		case ')':
-			level--
-			if i != len(str)-1 {
-				continue
-			}
-			fallthrough
+// RemoveFunction - remove user's or default function, return false if it doesn't exist.
+// Functions defined by DefineFunction which call it keep its last definition
+func (p *Parser) RemoveFunction(s string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.definitions, s)
+	return p.functions.Remove(0, s)
+}
 
-		default:
-			if level > 0 {
-				continue
+// RegisterOperator - add user's binary, prefix or postfix operator, like '//' or 'n!',
+// with its priority level and associativity
+func (p *Parser) RegisterOperator(op funcs.Operator) error {
+	return p.functions.RegisterOperator(op)
+}
 
-			} else if c == ',' || i == len(str)-1 {
-				//fmt.Println("start:", start, "i:", i)
-				args = append(args, str[start:i])
-				start = i + 1
+// RemoveOperator - remove user's or default operator of the fixity, return false if it doesn't exist
+func (p *Parser) RemoveOperator(fixity funcs.Fixity, name string) bool {
+	return p.functions.RemoveOperator(fixity, name)
+}
 
-			}
-		}
-	}
+// PercentMode - the meaning of '%' in expressions
+type PercentMode int
 
-	// fmt.Println("Func " + f.Op + " args:")
-	// for i, elem := range args {
-	// 	fmt.Println(strconv.Itoa(i) + ".   '" + string(elem) + "'")
-	// }
-	// fmt.Println("End func " + f.Op + " args.")
+const (
+	PercentRemainder PercentMode = iota // '7 % 4' is the remainder 3, the default
+	PercentLiteral                      // '15%' is 0.15, so '200 * 15%' is 30 and '50 + 10%' is 50.1
+)
 
-	for _, elem := range args {
-		arg, err := p.parseStr(elem)
-		if err != nil {
-			return f, true, err
-		}
-		f.SetArgs(append(f.GetArgs(), arg))
+// SetPercentMode - change the meaning of '%' in expressions parsed after the call.
+// The operator is replaced in the registry of functions of the Parser
+func (p *Parser) SetPercentMode(m PercentMode) error {
+	switch m {
+	case PercentRemainder:
+		p.functions.RemoveOperator(funcs.Postfix, "%")
+		return p.functions.RegisterOperator(dfuncs.RemainderOperator)
+	case PercentLiteral:
+		p.functions.RemoveOperator(funcs.Infix, "%")
+		return p.functions.RegisterOperator(dfuncs.PercentOperator)
 	}
-
-	return f, true, nil
+	return errors.New("incorrect percent mode: " + strconv.Itoa(int(m)))
 }
 
-func (p *Parser) parseStr(str []rune) (interfaces.Expression, error) {
-	if len(str) == 0 {
-		return &internal.Term{Val: decimal.Zero.String()}, nil
-	}
-	level := 0
-
-	for priorityLevel := 2; priorityLevel >= 1; priorityLevel-- {
-		for i := len(str) - 1; i >= 0; i-- {
-			c := str[i]
-			if c == ')' {
-				level++
-				continue
-			}
-			if c == '(' {
-				level--
-				continue
-			}
-			if level > 0 {
-				continue
-			}
-			if _, ok := p.GetFunctions()[priorityLevel][string(c)]; ok {
-				if i > 0 && isValidBinaryOperatorContext(str, i) {
-					left := str[0:i]
-					right := str[i+1:]
-					resL, err := p.parseStr(left)
-					if err != nil {
-						return nil, err
-					}
-					resR, err := p.parseStr(right)
-					if err != nil {
-						return nil, err
-					}
-					return &internal.Node{Op: string(c), LExp: resL, RExp: resR}, nil
-				}
-			}
-		}
+// PercentMode - the meaning of '%' in expressions
+func (p *Parser) PercentMode() PercentMode {
+	if _, ok := p.functions.LookupPostfix("%"); ok {
+		return PercentLiteral
 	}
+	return PercentRemainder
+}
 
-	level = 0
-	for i := 0; i < len(str); i++ {
-		c := str[i]
-		if c == '(' {
-			level++
-			continue
-		}
-		if c == ')' {
-			level--
-			continue
-		}
-		if level > 0 {
-			continue
-		}
-		if _, ok := p.GetFunctions()[0][string(c)]; ok {
-			if i == 0 {
-				right := str[i+1:]
-				resR, err := p.parseStr(right)
-				if err != nil {
-					return nil, err
-				}
-				return &internal.Unary{Op: string(c), Exp: resR}, nil
-			}
-		}
-	}
+// Functions - the registry of operators and functions used by the Parser
+func (p *Parser) Functions() *funcs.FunctionRegistry {
+	return p.functions
+}
 
-	// parse func
-	if f, isFunc, err := p.parseFunc(str); err != nil {
-		return nil, err
-	} else if isFunc {
-		return f.(interfaces.Expression), nil
-	}
+// Parse - parsing a string format math expression, return the compiled expression
+// bound to the current set of functions. A syntax error is returned as *ParseError
+func (p *Parser) Parse(str string) (*CompiledExpression, error) {
+	return p.parse(str, false)
+}
+
+// ParseScript - parsing statements separated by ';' or line breaks, like 'base = price * qty; base * 1.2'.
+// A statement assigns the value of the expression to the local variable, which is visible to the next statements,
+// or it is an expression. The result of the script is the value of the last statement.
+// A line break inside parentheses or after an operator doesn't end the statement.
+// GetVarList reports only variables which are read before their assignment
+func (p *Parser) ParseScript(str string) (*CompiledExpression, error) {
+	return p.parse(str, true)
+}
 
-	if str[0] == '(' {
-		for i, c := range str {
-			if c == '(' {
-				level++
-				continue
-			}
-			if c == ')' {
-				level--
-				if level == 0 {
-					exp := str[1:i]
-					return p.parseStr(exp)
-				}
-				continue
-			}
+func (p *Parser) parse(str string, script bool) (*CompiledExpression, error) {
+	// the clone keeps the functions, so later changes of the Parser don't affect the expression
+	ctx := evalContext{functions: p.functions.Clone(), settings: p.Settings()}
+	opts := parseOptions{constants: *p.constants.Load(), implicitMul: p.implicitMul.Load(), script: script}
+	ep, res, err := parseStrWith(str, ctx, opts)
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.locate(str)
 		}
-	} else {
-		return &internal.Term{Val: string(str)}, nil
+		return nil, err
 	}
-	return nil, errors.New("unknow internal error")
+	return &CompiledExpression{source: str, root: res, ctx: ctx, positions: ep.positions}, nil
 }
 
 // GetVarList - return list of variables which are used in the expression
-func GetVarList(expr interfaces.Expression) []string {
+func GetVarList(expr interfaces.VarLister) []string {
 	vars := make(map[string]interface{})
 	expr.GetVarList(vars)
 
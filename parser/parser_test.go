@@ -2,10 +2,13 @@ package parser
 
 import (
 	"errors"
+	"reflect"
 	"sort"
 	"strconv"
 	"testing"
 
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	dfuncs "github.com/arconomy/go-math-expression-parser/funcs/basic"
 	"github.com/shopspring/decimal"
 )
 
@@ -86,8 +89,11 @@ func TestNewParser(t *testing.T) {
 
 	data := []string{"f1(1)", "f2(2)"}
 
-	parser1.Parse(data[0])
-	res, err := parser1.Evaluate(map[string]decimal.Decimal{"a": decimal.NewFromFloat(1)})
+	exp, err := parser1.Parse(data[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := exp.Evaluate(map[string]decimal.Decimal{"a": decimal.NewFromFloat(1)})
 	if err != nil {
 		t.Error(err)
 	}
@@ -95,8 +101,11 @@ func TestNewParser(t *testing.T) {
 		t.Error("incorrect parser1 result, need: 101.0, but get: " + res.String())
 	}
 
-	parser1.Parse(data[1])
-	res, err = parser1.Evaluate(map[string]decimal.Decimal{})
+	exp, err = parser1.Parse(data[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = exp.Evaluate(map[string]decimal.Decimal{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -104,8 +113,11 @@ func TestNewParser(t *testing.T) {
 		t.Error("incorrect parser1 result, need: 202.0, but get: " + res.String())
 	}
 
-	parser2.Parse(data[0])
-	res, err = parser2.Evaluate(map[string]decimal.Decimal{})
+	exp, err = parser2.Parse(data[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = exp.Evaluate(map[string]decimal.Decimal{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -113,8 +125,11 @@ func TestNewParser(t *testing.T) {
 		t.Error("incorrect parser2 result, need: 201.0, but get: " + res.String())
 	}
 
-	parser2.Parse(data[1])
-	res, err = parser2.Evaluate(map[string]decimal.Decimal{})
+	exp, err = parser2.Parse(data[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = exp.Evaluate(map[string]decimal.Decimal{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -136,11 +151,12 @@ func TestNewParser2(t *testing.T) {
 	}
 
 	for _, d := range data {
-		_, err := parser.Parse(d.input)
+		exp, err := parser.Parse(d.input)
 		if err != nil {
 			t.Error(err)
+			continue
 		}
-		res, err := parser.Evaluate(map[string]decimal.Decimal{})
+		res, err := exp.Evaluate(map[string]decimal.Decimal{})
 		if err != nil {
 			t.Error(err)
 		}
@@ -166,14 +182,21 @@ func TestParse(t *testing.T) {
 		{"sqrt(3^2+(2*2+3))", decimal.NewFromInt(4)},
 		{"100+sqrt(3^2+(2*2+3))", decimal.NewFromInt(104)},
 		{"2*-1", decimal.NewFromInt(-2)},
+		{"2^3^2", decimal.NewFromInt(512)},
+		{"-2^2", decimal.NewFromInt(-4)},
+		{"2*3^2", decimal.NewFromInt(18)},
+		// like decimal.Pow
+		{"0^0", decimal.NewFromInt(1)},
+		{"0^0.5", decimal.Zero},
 	}
 	parser := NewParser()
 	for _, d := range data {
-		_, err := parser.Parse(d.input)
+		exp, err := parser.Parse(d.input)
 		if err != nil {
 			t.Error(err)
+			continue
 		}
-		res, err := parser.Evaluate(map[string]decimal.Decimal{})
+		res, err := exp.Evaluate(map[string]decimal.Decimal{})
 		if err != nil {
 			t.Error(err)
 		}
@@ -185,25 +208,25 @@ func TestParse(t *testing.T) {
 
 func TestParserString(t *testing.T) {
 	p := NewParser()
-	p.Parse("")
-	if p.String() != "0" {
-		t.Error("incorrect string conversion = " + p.String())
+	exp, _ := p.Parse("")
+	if exp.String() != "0" {
+		t.Error("incorrect string conversion = " + exp.String())
 	}
-	p.Parse("1 + a")
-	if p.String() != "( + 1 a )" {
-		t.Error("incorrect string conversion = " + p.String())
+	exp, _ = p.Parse("1 + a")
+	if exp.String() != "( + 1 a )" {
+		t.Error("incorrect string conversion = " + exp.String())
 	}
-	p.Parse("2^(sqrt(14+2))")
-	if p.String() != "( ^ 2 ( sqrt ( ( + 14 2 ) ) ) )" {
-		t.Error("incorrect string conversion = " + p.String())
+	exp, _ = p.Parse("2^(sqrt(14+2))")
+	if exp.String() != "( ^ 2 ( sqrt ( ( + 14 2 ) ) ) )" {
+		t.Error("incorrect string conversion = " + exp.String())
 	}
-	p.Parse("abs(- 2)")
-	if p.String() != "( abs ( ( - 2 ) ) )" {
-		t.Error("incorrect string conversion = " + p.String())
+	exp, _ = p.Parse("abs(- 2)")
+	if exp.String() != "( abs ( ( - 2 ) ) )" {
+		t.Error("incorrect string conversion = " + exp.String())
 	}
-	p.Parse("- 4")
-	if p.String() != "( - 4 )" {
-		t.Error("incorrect string conversion = " + p.String())
+	exp, _ = p.Parse("- 4")
+	if exp.String() != "( - 4 )" {
+		t.Error("incorrect string conversion = " + exp.String())
 	}
 }
 
@@ -220,14 +243,1050 @@ func TestParse2(t *testing.T) {
 }
 
 func TestParseStr(t *testing.T) {
-	//p := Parser{}
-	//i, err := p.parseStr([]rune("asf"))
-	// TODO: finish
+	type TestData struct {
+		input  string
+		output string
+	}
+	data := []TestData{
+		{"1-2-3", "( - ( - 1 2 ) 3 )"},
+		{"1-2*3", "( - 1 ( * 2 3 ) )"},
+		{"2*-1", "( * 2 ( - 1 ) )"},
+		{"-2^2", "( - ( ^ 2 2 ) )"},
+		{"2^3^2", "( ^ 2 ( ^ 3 2 ) )"},
+		{"2*3^2", "( * 2 ( ^ 3 2 ) )"},
+		{"2^3*2", "( * ( ^ 2 3 ) 2 )"},
+		{"2^-1", "( ^ 2 ( - 1 ) )"},
+		{"-2*3", "( * ( - 2 ) 3 )"},
+		{"(-2)^2", "( ^ ( - 2 ) 2 )"},
+		{"2^-3^2", "( ^ 2 ( - ( ^ 3 2 ) ) )"},
+		{"!x^2", "( ! ( ^ x 2 ) )"},
+		{"8/4/2", "( / ( / 8 4 ) 2 )"},
+		{"--x", "( - ( - x ) )"},
+		{"(((x)))", "x"},
+		{"x1*(x2^2)", "( * x1 ( ^ x2 2 ) )"},
+	}
+	p := NewParser()
+	for _, d := range data {
+		exp, err := parseStr(d.input, p)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+	}
+
+	for _, s := range []string{"1+", "*2", "(1+2", "1+2)", "1 2", "2#3", "sqrt(1,", "x(2)"} {
+		if _, err := parseStr(s, p); err == nil {
+			t.Error("error was not handled for '" + s + "'")
+		}
+	}
 }
 
 func TestParseFunc(t *testing.T) {
-	//p := Parser{}
-	//_, isFunc, err := p.parseFunc([]rune("foo(a+b)"))
-	// TODO: finish
+	p := NewParser()
+	p.AddFunction(func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.NewFromInt(int64(len(args))), nil
+	}, "count")
+
+	type TestData struct {
+		input  string
+		output string
+	}
+	data := []TestData{
+		{"count()", "( count (  ) )"},
+		{"count(a+b)", "( count ( ( + a b ) ) )"},
+		{"count(1,(2),sqrt(3))", "( count ( 1,2,( sqrt ( 3 ) ) ) )"},
+		{"count(count(1,2),3)*2", "( * ( count ( ( count ( 1,2 ) ),3 ) ) 2 )"},
+	}
+	for _, d := range data {
+		exp, err := parseStr(d.input, p)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+	}
+}
+
+func BenchmarkParseLong(b *testing.B) {
+	s := "x"
+	for i := 0; i < 5000; i++ {
+		s += "+" + strconv.Itoa(i) + "*(y-sqrt(" + strconv.Itoa(i) + "))"
+	}
+	p := NewParser()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Parse(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParseArgsCount(t *testing.T) {
+	p := NewParser()
+	err := p.RegisterFunction(funcs.Descriptor{
+		Name: "clamp", MinArgs: 2, MaxArgs: 3,
+		Func: func(args ...decimal.Decimal) (decimal.Decimal, error) {
+			return args[0], nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"sqrt(4)", "abs(-1) + sqrt(1)", "clamp(1, 2)", "clamp(1, 2, 3)"} {
+		if _, err := p.Parse(s); err != nil {
+			t.Error(err)
+		}
+	}
+
+	for _, s := range []string{"sqrt(1, 2)", "sqrt()", "1 + abs(1, 2, 3)", "clamp(1)", "clamp(1, 2, 3, 4)"} {
+		if _, err := p.Parse(s); err == nil {
+			t.Error("incorrect count of args was not handled for '" + s + "'")
+		}
+	}
+
+	_, err = p.Parse("1 + sqrt(1, 2)")
+	if err == nil || err.Error() != "incorrect count of args for 'sqrt' function. Need: 1, but get: 2 at line 1, column 5" {
+		t.Error("incorrect error: ", err)
+	}
+}
+
+func TestParseLogic(t *testing.T) {
+	type TestData struct {
+		input  string
+		tree   string
+		output decimal.Decimal
+	}
+
+	vars := map[string]decimal.Decimal{
+		"qty":   decimal.NewFromInt(12),
+		"total": decimal.NewFromInt(600),
+	}
+	data := []TestData{
+		{"qty >= 10 && total > 500", "( && ( >= qty 10 ) ( > total 500 ) )", decimal.NewFromInt(1)},
+		{"qty >= 20 || total > 500 && qty < 10", "( || ( >= qty 20 ) ( && ( > total 500 ) ( < qty 10 ) ) )", decimal.Zero},
+		{"qty + 1 == 13", "( == ( + qty 1 ) 13 )", decimal.NewFromInt(1)},
+		{"qty*2 != 24", "( != ( * qty 2 ) 24 )", decimal.Zero},
+		{"!qty == 0", "( == ( ! qty ) 0 )", decimal.NewFromInt(1)},
+		{"!(qty <= 12)", "( ! ( <= qty 12 ) )", decimal.Zero},
+		{"(qty > 10) * 5", "( * ( > qty 10 ) 5 )", decimal.NewFromInt(5)},
+	}
+
+	p := NewParser()
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.tree {
+			t.Error("incorrect tree for '" + d.input + "': " + exp.String())
+		}
+		res, err := exp.Evaluate(vars)
+		if err != nil {
+			t.Error(err)
+		}
+		if !res.Equal(d.output) {
+			t.Error("incorrect result for '" + d.input + "', need: " + d.output.String() + ", but get: " + res.String())
+		}
+	}
+
+	for _, s := range []string{"qty & 1", "qty = 1", "qty <> 1", "qty ! 1"} {
+		if _, err := p.Parse(s); err == nil {
+			t.Error("error was not handled for '" + s + "'")
+		}
+	}
+}
+
+func TestParseConditional(t *testing.T) {
+	type TestData struct {
+		input  string
+		tree   string
+		vars   map[string]decimal.Decimal
+		output decimal.Decimal
+	}
+
+	zero := map[string]decimal.Decimal{"x": decimal.Zero, "y": decimal.NewFromInt(10)}
+	five := map[string]decimal.Decimal{"x": decimal.NewFromInt(5), "y": decimal.NewFromInt(10)}
+	data := []TestData{
+		{"if(x == 0, 0, y / x)", "( if ( ( == x 0 ),0,( / y x ) ) )", zero, decimal.Zero},
+		{"if(x == 0, 0, y / x)", "( if ( ( == x 0 ),0,( / y x ) ) )", five, decimal.NewFromInt(2)},
+		{"x == 0 ? 0 : y / x", "( if ( ( == x 0 ),0,( / y x ) ) )", zero, decimal.Zero},
+		{"x > 1 || y < 0 ? x : -x", "( if ( ( || ( > x 1 ) ( < y 0 ) ),x,( - x ) ) )", five, decimal.NewFromInt(5)},
+		{"x < 0 ? -1 : x == 0 ? 0 : 1", "( if ( ( < x 0 ),( - 1 ),( if ( ( == x 0 ),0,1 ) ) ) )", five, decimal.NewFromInt(1)},
+		{"x ? y ? 1 : 2 : 3", "( if ( x,( if ( y,1,2 ) ),3 ) )", five, decimal.NewFromInt(1)},
+		{"1 + (x ? 2 : 3) * 2", "( + 1 ( * ( if ( x,2,3 ) ) 2 ) )", zero, decimal.NewFromInt(7)},
+		{"x != 0 && y / x > 1", "( && ( != x 0 ) ( > ( / y x ) 1 ) )", zero, decimal.Zero},
+		{"x == 0 || y / x > 1", "( || ( == x 0 ) ( > ( / y x ) 1 ) )", zero, decimal.NewFromInt(1)},
+	}
+
+	p := NewParser()
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.tree {
+			t.Error("incorrect tree for '" + d.input + "': " + exp.String())
+		}
+		res, err := exp.Evaluate(d.vars)
+		if err != nil {
+			t.Error(err)
+		}
+		if !res.Equal(d.output) {
+			t.Error("incorrect result for '" + d.input + "', need: " + d.output.String() + ", but get: " + res.String())
+		}
+	}
+
+	for _, s := range []string{"x ? 1", "x ? 1 :", "? 1 : 2", "x : 1", "if(x, 1)"} {
+		if _, err := p.Parse(s); err == nil {
+			t.Error("error was not handled for '" + s + "'")
+		}
+	}
+
+	p.RemoveFunction("if")
+	if _, err := p.Parse("x ? 1 : 2"); err == nil {
+		t.Error("conditional operator was parsed without 'if' function")
+	}
+}
+
+func TestParseLiterals(t *testing.T) {
+	type TestData struct {
+		input  string
+		tree   string
+		vars   []string
+		output string
+	}
+
+	data := []TestData{
+		{"1.5e-3 * 2", "( * 1.5e-3 2 )", nil, "0.003"},
+		{"x-1e+2", "( - x 1e+2 )", []string{"x"}, "-99"},
+		{"1_000_000 / 0x10", "( / 1_000_000 0x10 )", nil, "62500"},
+		{"0b1010 + .5", "( + 0b1010 .5 )", nil, "10.5"},
+		{"inf + nan", "( + inf nan )", []string{"inf", "nan"}, "2"},
+		{"e1 + 2E2", "( + e1 2E2 )", []string{"e1"}, "201"},
+	}
+
+	p := NewParser()
+	vars := map[string]decimal.Decimal{"x": decimal.NewFromInt(1), "inf": decimal.NewFromInt(1),
+		"nan": decimal.NewFromInt(1), "e1": decimal.NewFromInt(1)}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.tree {
+			t.Error("incorrect tree for '" + d.input + "': " + exp.String())
+		}
+		if v := GetVarList(exp); !reflect.DeepEqual(v, d.vars) {
+			t.Error("incorrect variables of '"+d.input+"': ", v)
+		}
+		res, err := exp.Evaluate(vars)
+		if err != nil {
+			t.Error(err)
+		} else if !res.Equal(decimal.RequireFromString(d.output)) {
+			t.Error("incorrect result for '" + d.input + "', need: " + d.output + ", but get: " + res.String())
+		}
+	}
+
+	for _, s := range []string{"1e99999999999", "0b12", "1__0", "1_", "1.5.3"} {
+		if _, err := p.Parse(s); err == nil {
+			t.Error("error was not handled for '" + s + "'")
+		}
+	}
+}
+
+func TestParseWhitespace(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+	}
+	data := []TestData{
+		{" 1 +\t2 ", "( + 1 2 )"},
+		{"a\n*\r\nb", "( * a b )"},
+		{"sqrt (4)", "( sqrt ( 4 ) )"},
+		{"x >= 1", "( >= x 1 )"},
+		{"1_000 + 2", "( + 1_000 2 )"},
+	}
+	p := NewParser()
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+	}
+
+	// spaces separate tokens, so they are never joined
+	for _, s := range []string{"a b", "2 3", "1 000", "x (1)", "(a) (b)", "1 > = 2", "sq rt(4)", "1. 5"} {
+		_, err := p.Parse(s)
+		if err == nil {
+			t.Error("error was not handled for '" + s + "'")
+		}
+	}
+	_, err := p.Parse("a b")
+	if perr, ok := err.(*ParseError); !ok || perr.Msg != "missing operator between operands" {
+		t.Error("incorrect error for adjacent operands: ", err)
+	}
+}
+
+func TestParseImplicitMultiplication(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+	}
+	data := []TestData{
+		{"2x", "( * 2 x )"},
+		{"3(a+b)", "( * 3 ( + a b ) )"},
+		{"x(a+b)", "( * x ( + a b ) )"},
+		{"(a+b)(c-d)", "( * ( + a b ) ( - c d ) )"},
+		{"(a+b)x", "( * ( + a b ) x )"},
+		{"2 x", "( * 2 x )"},
+		{"2x1", "( * 2 x1 )"},
+		// a literal is never split: the exponent and the hex prefix belong to the number
+		{"2e3", "2e3"},
+		{"0xA", "0xA"},
+		// a function call is not a multiplication
+		{"2sqrt(x)", "( * 2 ( sqrt ( x ) ) )"},
+		{"sqrt(x)(y)", "( * ( sqrt ( x ) ) y )"},
+		// the precedence of '*', left associative
+		{"2x + 1", "( + ( * 2 x ) 1 )"},
+		{"2x * y", "( * ( * 2 x ) y )"},
+		{"1 / 2x", "( * ( / 1 2 ) x )"},
+		{"-2x", "( * ( - 2 ) x )"},
+		{"2x > 1 && y", "( && ( > ( * 2 x ) 1 ) y )"},
+		// constants are resolved before the multiplication
+		{"2pi", "( * 2 3.1415926535897932 )"},
+	}
+	p := NewParser()
+	if p.ImplicitMultiplication() {
+		t.Error("implicit multiplication is enabled by default")
+	}
+	p.SetImplicitMultiplication(true)
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+	}
+
+	exp, err := p.Parse("3(a+b)(a-b)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := exp.Evaluate(map[string]decimal.Decimal{"a": decimal.NewFromInt(3), "b": decimal.NewFromInt(1)})
+	if err != nil || !res.Equal(decimal.NewFromInt(24)) {
+		t.Error("incorrect result of implicit multiplication: ", res, err)
+	}
+
+	// numbers and names are not multiplied if a number or a name follows them
+	for _, s := range []string{"a b", "2 3", "x2 3", "(a)2", "2 x 3"} {
+		_, err := p.Parse(s)
+		if perr, ok := err.(*ParseError); !ok || perr.Msg != "missing operator between operands" {
+			t.Error("incorrect error for '"+s+"': ", err)
+		}
+	}
+
+	if !p.Clone().ImplicitMultiplication() {
+		t.Error("implicit multiplication was not copied to the clone")
+	}
+	p.SetImplicitMultiplication(false)
+	if _, err := p.Parse("2x"); err == nil {
+		t.Error("implicit multiplication was not disabled")
+	}
+}
+
+func TestParseOperators(t *testing.T) {
+	intDiv := func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return args[0].Div(args[1]).Floor(), nil
+	}
+	maxOf := func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.Max(args[0], args[1]), nil
+	}
+	double := func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return args[0].Mul(decimal.NewFromInt(2)), nil
+	}
+	half := func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return args[0].Div(decimal.NewFromInt(2)), nil
+	}
+
+	p := NewParser()
+	for _, op := range []funcs.Operator{
+		{Name: "//", Fixity: funcs.Infix, Level: funcs.LevelMultiplicative, Func: intDiv},
+		{Name: "max|", Fixity: funcs.Infix, Level: funcs.LevelComparison, Func: maxOf},
+		{Name: "**", Fixity: funcs.Infix, Level: funcs.LevelPower, Associativity: funcs.RightAssoc, Func: dfuncs.Pow},
+		{Name: "~", Fixity: funcs.Prefix, Level: funcs.LevelUnary, Func: double},
+		{Name: "½", Fixity: funcs.Postfix, Level: funcs.LevelUnary, Func: half},
+		{Name: "%", Fixity: funcs.Postfix, Level: funcs.LevelUnary, Func: half},
+	} {
+		if err := p.RegisterOperator(op); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type TestData struct {
+		input  string
+		output string
+		res    int64
+	}
+	data := []TestData{
+		{"7 // 2 * 3", "( * ( // 7 2 ) 3 )", 9},
+		{"1 + 7 // 2", "( + 1 ( // 7 2 ) )", 4},
+		{"1 + 2 max| 5", "( max| ( + 1 2 ) 5 )", 5},
+		{"2 ** 3 ** 2", "( ** 2 ( ** 3 2 ) )", 512},
+		{"~3^2", "( ^ ( ~ 3 ) 2 )", 36},
+		{"-3^2", "( - ( ^ 3 2 ) )", -9},
+		{"2^4½", "( ^ 2 ( 4 ½ ) )", 4},
+		{"-4½", "( - ( 4 ½ ) )", -2},
+		{"(2 + 6)½½", "( ( ( + 2 6 ) ½ ) ½ )", 2},
+		// the binary operator wins if an operand follows
+		{"8 % 3", "( % 8 3 )", 2},
+		{"8% - 3", "( - ( 8 % ) 3 )", 1},
+		{"8% * 2", "( * ( 8 % ) 2 )", 8},
+	}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		res, err := exp.Evaluate(nil)
+		if err != nil || !res.Equal(decimal.NewFromInt(d.res)) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+
+	// 'max(' is not 'max|'
+	numericMax := func(args ...decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.Max(args[0], args[1:]...), nil
+	}
+	p.AddFunction(numericMax, "max")
+	if exp, err := p.Parse("max(maxi, 2)"); err != nil || exp.String() != "( max ( maxi,2 ) )" {
+		t.Error("incorrect function call after an operator with letters: ", exp, err)
+	}
+
+	for _, op := range []funcs.Operator{
+		{Name: "div", Fixity: funcs.Infix, Level: funcs.LevelMultiplicative, Func: intDiv},
+		{Name: "1+", Fixity: funcs.Infix, Level: funcs.LevelMultiplicative, Func: intDiv},
+		{Name: "(", Fixity: funcs.Prefix, Func: double},
+		{Name: "=", Fixity: funcs.Infix, Level: funcs.LevelComparison, Func: maxOf},
+		{Name: ";", Fixity: funcs.Infix, Level: funcs.LevelMultiplicative, Func: maxOf},
+		{Name: "//", Fixity: funcs.Infix, Level: funcs.LevelUnary, Func: intDiv},
+		{Name: "//", Fixity: funcs.Infix, Level: funcs.LevelsOfPriorities, Func: intDiv},
+		{Name: "//", Fixity: funcs.Infix, Level: funcs.LevelMultiplicative},
+	} {
+		if err := p.RegisterOperator(op); err == nil {
+			t.Error("incorrect operator was registered: " + op.Name)
+		}
+	}
+
+	if !p.RemoveOperator(funcs.Postfix, "½") || p.RemoveOperator(funcs.Postfix, "½") {
+		t.Error("incorrect removal of the postfix operator")
+	}
+	if _, err := p.Parse("4½"); err == nil {
+		t.Error("removed operator was parsed")
+	}
+	if !p.RemoveOperator(funcs.Infix, "//") {
+		t.Error("binary operator was not removed")
+	}
+	if _, err := p.Parse("7 // 2"); err == nil {
+		t.Error("removed operator was parsed")
+	}
+}
 
+func TestParsePostfix(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+		res    string
+	}
+	p := NewParser()
+	data := []TestData{
+		{"5!", "( 5 ! )", "120"},
+		{"0!", "( 0 ! )", "1"},
+		{"-3!", "( - ( 3 ! ) )", "-6"},
+		{"2^3!", "( ^ 2 ( 3 ! ) )", "64"},
+		{"3!^2", "( ^ ( 3 ! ) 2 )", "36"},
+		{"3!!", "( ( 3 ! ) ! )", "720"},
+		{"!0!", "( ! ( 0 ! ) )", "0"},
+		{"(1 + 2)! / 2", "( / ( ( + 1 2 ) ! ) 2 )", "3"},
+		// '!=' is a single token
+		{"3! != 6", "( != ( 3 ! ) 6 )", "0"},
+		{"7 % 4", "( % 7 4 )", "3"},
+	}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		res, err := exp.Evaluate(nil)
+		if err != nil || !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+	for _, s := range []string{"(-1)!", "2.5!", "10001!"} {
+		exp, err := p.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := exp.Evaluate(nil); err == nil {
+			t.Error("error was not handled for '" + s + "'")
+		}
+	}
+
+	if p.PercentMode() != PercentRemainder {
+		t.Error("incorrect default percent mode")
+	}
+	if _, err := p.Parse("15%"); err == nil {
+		t.Error("percent was parsed in the remainder mode")
+	}
+	clone := p.Clone()
+	if err := p.SetPercentMode(PercentLiteral); err != nil {
+		t.Fatal(err)
+	}
+	if p.PercentMode() != PercentLiteral || clone.PercentMode() != PercentRemainder {
+		t.Error("incorrect percent mode after the change")
+	}
+	data = []TestData{
+		{"15%", "( 15 % )", "0.15"},
+		{"200 * 15%", "( * 200 ( 15 % ) )", "30"},
+		{"50 + 10%", "( + 50 ( 10 % ) )", "50.1"},
+		{"price * (1 - discount%)", "( * price ( - 1 ( discount % ) ) )", "90"},
+		{"-5%", "( - ( 5 % ) )", "-0.05"},
+	}
+	vars := map[string]decimal.Decimal{"price": decimal.NewFromInt(100), "discount": decimal.NewFromInt(10)}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		res, err := exp.Evaluate(vars)
+		if err != nil || !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+	if _, err := p.Parse("7 % 4"); err == nil {
+		t.Error("remainder was parsed in the percent mode")
+	}
+
+	if err := p.SetPercentMode(PercentRemainder); err != nil {
+		t.Fatal(err)
+	}
+	if exp, err := p.Parse("7 % 4"); err != nil || exp.String() != "( % 7 4 )" {
+		t.Error("remainder was not restored: ", err)
+	}
+	if err := p.SetPercentMode(PercentMode(2)); err == nil {
+		t.Error("incorrect percent mode was accepted")
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+		vars   []string
+		res    string
+	}
+	data := []TestData{
+		{"base = price * qty; tax = base * 0.2; base + tax",
+			"( = base ( * price qty ) ); ( = tax ( * base 0.2 ) ); ( + base tax )", []string{"price", "qty"}, "24"},
+		{"base = price * qty\ntax = base * 0.2\n\nbase + tax\n",
+			"( = base ( * price qty ) ); ( = tax ( * base 0.2 ) ); ( + base tax )", []string{"price", "qty"}, "24"},
+		// a line break inside parentheses or after an operator continues the statement
+		{"total = (price\n* qty) +\n1\ntotal", "( = total ( + ( * price qty ) 1 ) ); total", []string{"price", "qty"}, "21"},
+		// the result is the value of the last statement, even if it is an assignment
+		{"x = price * 2", "( = x ( * price 2 ) )", []string{"price"}, "20"},
+		// a variable which is read before the assignment is an input
+		{"qty = qty + 1; price * qty", "( = qty ( + qty 1 ) ); ( * price qty )", []string{"price", "qty"}, "30"},
+		{"a = 1; a = a + 1; a * 3", "( = a 1 ); ( = a ( + a 1 ) ); ( * a 3 )", nil, "6"},
+		{"qty == 2 ? 1 : 0", "( if ( ( == qty 2 ),1,0 ) )", []string{"qty"}, "1"},
+		{";;", "0", nil, "0"},
+		{"", "0", nil, "0"},
+	}
+
+	p := NewParser()
+	vars := map[string]decimal.Decimal{"price": decimal.NewFromInt(10), "qty": decimal.NewFromInt(2)}
+	for _, d := range data {
+		exp, err := p.ParseScript(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		if v := GetVarList(exp); !reflect.DeepEqual(v, d.vars) {
+			t.Error("incorrect variables of '"+d.input+"': ", v)
+		}
+		res, err := exp.Evaluate(vars)
+		if err != nil || !res.Equal(decimal.RequireFromString(d.res)) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+	if !vars["qty"].Equal(decimal.NewFromInt(2)) || len(vars) != 2 {
+		t.Error("the script changed the variables of the caller: ", vars)
+	}
+
+	type ErrorData struct {
+		input string
+		err   string
+	}
+	errData := []ErrorData{
+		{"a = 1; b c", "missing operator between operands at line 1, column 10"},
+		{"a = 1\nb = ", "unexpected end of expression at line 2, column 5: expected operand"},
+		{"pi = 3", "cannot assign to constant 'pi' at line 1, column 1"},
+		{"sqrt = 3", "cannot assign to function 'sqrt' at line 1, column 1"},
+		{"a = 1 = 2", "unexpected '=' at line 1, column 7: expected operator or ';' or line break"},
+		{"1 + (2\n; 3)", "unexpected ';' at line 2, column 1: expected ')'"},
+		{"2 * 3 = x", "unexpected '=' at line 1, column 7: expected operator or ';' or line break"},
+	}
+	for _, d := range errData {
+		_, err := p.ParseScript(d.input)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+
+	// a single expression has no statements
+	for _, s := range []string{"a = 1", "1; 2", "1\n2"} {
+		if _, err := p.Parse(s); err == nil {
+			t.Error("error was not handled for '" + s + "'")
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+		vars   []string
+		res    funcs.Value
+	}
+	num := func(s string) funcs.Value {
+		return funcs.NumberValue(decimal.RequireFromString(s))
+	}
+	data := []TestData{
+		{`currency == "USD"`, `( == currency "USD" )`, []string{"currency"}, funcs.BoolValue(true)},
+		{`if(currency == "USD", price, price * 1.1)`, `( if ( ( == currency "USD" ),price,( * price 1.1 ) ) )`, []string{"currency", "price"}, num("10")},
+		{`vip ? "gold" : "basic"`, `( if ( vip,"gold","basic" ) )`, []string{"vip"}, funcs.StringValue("gold")},
+		{`concat(upper(currency), " ", price * qty)`, `( concat ( ( upper ( currency ) )," ",( * price qty ) ) )`, []string{"currency", "price", "qty"}, funcs.StringValue("USD 20")},
+		{`len("доход\t")`, `( len ( "доход\t" ) )`, nil, num("6")},
+		{"discount == null", "( == discount null )", []string{"discount"}, funcs.BoolValue(true)},
+		{"coalesce(discount, 0.1) * price", "( * ( coalesce ( discount,0.1 ) ) price )", []string{"discount", "price"}, num("1")},
+		{"vip && !false", "( && vip ( ! false ) )", []string{"vip"}, funcs.BoolValue(true)},
+		{"qty > 1 || missing", "( || ( > qty 1 ) missing )", []string{"missing", "qty"}, funcs.BoolValue(true)},
+		{"vip + 1", "( + vip 1 )", []string{"vip"}, num("2")},
+		{`number("2.5") * qty`, `( * ( number ( "2.5" ) ) qty )`, []string{"qty"}, num("5")},
+		{`number("0xFF") == 0xFF && number("1_000") == 1_000`, `( && ( == ( number ( "0xFF" ) ) 0xFF ) ( == ( number ( "1_000" ) ) 1_000 ) )`, nil, funcs.BoolValue(true)},
+		{`"a" < "b"`, `( < "a" "b" )`, nil, funcs.BoolValue(true)},
+	}
+
+	p := NewParser()
+	vars := map[string]funcs.Value{
+		"currency": funcs.StringValue("USD"),
+		"price":    num("10"),
+		"qty":      num("2"),
+		"vip":      funcs.BoolValue(true),
+		"discount": funcs.Null,
+	}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		if v := GetVarList(exp); !reflect.DeepEqual(v, d.vars) {
+			t.Error("incorrect variables of '"+d.input+"': ", v)
+		}
+		res, err := exp.EvaluateValue(vars)
+		if err != nil || !res.Equal(d.res) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+
+	type ErrorData struct {
+		input string
+		err   string
+	}
+	errData := []ErrorData{
+		{`price + "USD"`, "incorrect type of argument 2 of '+'. Need: number, but get: string"},
+		{`currency == 1`, "incorrect type of argument 2 of '=='. Need: string, but get: number"},
+		{`sqrt(discount)`, "incorrect type of argument 1 of 'sqrt'. Need: number, but get: null"},
+		{`-currency`, "incorrect type of argument 1 of '-'. Need: number, but get: string"},
+		{`currency ? 1 : 2`, "incorrect type of argument 1 of 'if'. Need: bool, but get: string"},
+		{`upper(price)`, "incorrect type of argument 1 of 'upper'. Need: string, but get: number"},
+	}
+	for _, d := range errData {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		_, err = exp.EvaluateValue(vars)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+
+	// the numeric evaluation converts bools to 1 and 0, other types are errors
+	exp, _ := p.Parse(`qty > 1`)
+	if res, err := exp.Evaluate(map[string]decimal.Decimal{"qty": decimal.NewFromInt(2)}); err != nil || !res.Equal(funcs.True) {
+		t.Error("incorrect numeric result: ", res, err)
+	}
+	exp, _ = p.Parse(`"USD"`)
+	if _, err := exp.Evaluate(nil); err == nil || err.Error() != "incorrect type of the result. Need: number, but get: string" {
+		t.Error("incorrect numeric error: ", err)
+	}
+
+	parseErrors := []ErrorData{
+		{`"USD`, "unterminated string at line 1, column 1"},
+		{`"\q"`, `incorrect string "\q" at line 1, column 1`},
+		{`true(1)`, "missing operator between operands at line 1, column 5"},
+		{`"a" "b"`, "missing operator between operands at line 1, column 5"},
+	}
+	for _, d := range parseErrors {
+		_, err := p.Parse(d.input)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+	if err := p.SetConstant("null", decimal.Zero); err == nil {
+		t.Error("keyword was accepted as a constant name")
+	}
+}
+
+func TestParseLists(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+		vars   []string
+		res    funcs.Value
+	}
+	num := func(s string) funcs.Value {
+		return funcs.NumberValue(decimal.RequireFromString(s))
+	}
+	data := []TestData{
+		{"sum(lines) * (1 - discount)", "( * ( sum ( lines ) ) ( - 1 discount ) )", []string{"discount", "lines"}, num("54")},
+		{"[1, x, 3]", "[ 1,x,3 ]", []string{"x"}, funcs.ListValue(num("1"), num("2"), num("3"))},
+		{"[]", "[  ]", nil, funcs.ListValue()},
+		{"lines[0] + lines[-1]", "( + ( lines [ 0 ] ) ( lines [ ( - 1 ) ] ) )", []string{"lines"}, num("40")},
+		{"[[1, 2], [3]][0][1]", "( ( [ [ 1,2 ],[ 3 ] ] [ 0 ] ) [ 1 ] )", nil, num("2")},
+		{"-lines[1]^2", "( - ( ^ ( lines [ 1 ] ) 2 ) )", []string{"lines"}, num("-400")},
+		{"avg(lines, 30)", "( avg ( lines,30 ) )", []string{"lines"}, num("22.5")},
+		{"min(lines) + max(lines, [5, 50])", "( + ( min ( lines ) ) ( max ( lines,[ 5,50 ] ) ) )", []string{"lines"}, num("60")},
+		{"count([1, \"a\", null])", `( count ( [ 1,"a",null ] ) )`, nil, num("3")},
+	}
+
+	p := NewParser()
+	vars := map[string]funcs.Value{
+		"lines":    funcs.ListValue(num("10"), num("20"), num("30")),
+		"discount": num("0.1"),
+		"x":        num("2"),
+	}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		if v := GetVarList(exp); !reflect.DeepEqual(v, d.vars) {
+			t.Error("incorrect variables of '"+d.input+"': ", v)
+		}
+		res, err := exp.EvaluateValue(vars)
+		if err != nil || !res.Equal(d.res) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+
+	// a line break inside brackets does not end a statement
+	exp, err := p.ParseScript("xs = [1,\n2]\nsum(xs)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := exp.Evaluate(nil); err != nil || !res.Equal(decimal.NewFromInt(3)) {
+		t.Error("incorrect result of the script: ", res, err)
+	}
+
+	type ErrorData struct {
+		input string
+		err   string
+	}
+	parseErrors := []ErrorData{
+		{"[1, 2", "unexpected end of expression at line 1, column 6: expected ',' or ']'"},
+		{"[1 2]", "missing operator between operands at line 1, column 4"},
+		{"xs[]", "unexpected ']' at line 1, column 4: expected operand"},
+		{"xs[1", "unexpected end of expression at line 1, column 5: expected ']'"},
+		{"(1]", "unexpected ']' at line 1, column 3: expected ')'"},
+	}
+	for _, d := range parseErrors {
+		_, err := p.Parse(d.input)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+
+	evalErrors := []ErrorData{
+		{"lines[3]", "index 3 is out of range of the list of 3 items"},
+		{"sum(lines, [x, \"a\"])", "incorrect type of item 2 of argument 2 of 'sum'. Need: number, but get: string"},
+		{"avg([])", "'avg' function got no values"},
+		{"lines * 2", "incorrect type of argument 1 of '*'. Need: number, but get: list"},
+	}
+	for _, d := range evalErrors {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		_, err = exp.EvaluateValue(vars)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+}
+
+func TestParseLambdas(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+		vars   []string
+		res    funcs.Value
+	}
+	num := func(s string) funcs.Value {
+		return funcs.NumberValue(decimal.RequireFromString(s))
+	}
+	data := []TestData{
+		{"map(xs, x -> x * 1.2)", "( map ( xs,( -> ( x ) ( * x 1.2 ) ) ) )", []string{"xs"}, funcs.ListValue(num("-1.2"), num("2.4"), num("3.6"))},
+		{"filter(xs, x -> x > 0)", "( filter ( xs,( -> ( x ) ( > x 0 ) ) ) )", []string{"xs"}, funcs.ListValue(num("2"), num("3"))},
+		{"reduce(xs, 0, (acc, x) -> acc + x)", "( reduce ( xs,0,( -> ( acc,x ) ( + acc x ) ) ) )", []string{"xs"}, num("4")},
+		{"sum(map(xs, x -> x * rate))", "( sum ( ( map ( xs,( -> ( x ) ( * x rate ) ) ) ) ) )", []string{"rate", "xs"}, num("8")},
+		{"map(xs, (x) -> x > 0 ? x : 0)", "( map ( xs,( -> ( x ) ( if ( ( > x 0 ),x,0 ) ) ) ) )", []string{"xs"}, funcs.ListValue(num("0"), num("2"), num("3"))},
+		{"map([[1, 2], [3]], ys -> reduce(ys, 0, (a, y) -> a + y * x))", "( map ( [ [ 1,2 ],[ 3 ] ],( -> ( ys ) ( reduce ( ys,0,( -> ( a,y ) ( + a ( * y x ) ) ) ) ) ) ) )",
+			[]string{"x"}, funcs.ListValue(num("15"), num("15"))},
+		{"count(filter(xs, x -> x != 2))", "( count ( ( filter ( xs,( -> ( x ) ( != x 2 ) ) ) ) ) )", []string{"xs"}, num("2")},
+	}
+
+	p := NewParser()
+	vars := map[string]funcs.Value{
+		"xs":   funcs.ListValue(num("-1"), num("2"), num("3")),
+		"rate": num("2"),
+		"x":    num("5"),
+	}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		if v := GetVarList(exp); !reflect.DeepEqual(v, d.vars) {
+			t.Error("incorrect variables of '"+d.input+"': ", v)
+		}
+		res, err := exp.EvaluateValue(vars)
+		if err != nil || !res.Equal(d.res) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+
+	// a lambda is a value, it captures the local variables of the script
+	exp, err := p.ParseScript("f = x -> x * k\nk = 2\nmap(xs, f)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := exp.EvaluateValue(map[string]funcs.Value{"xs": vars["xs"], "k": num("10")})
+	if err != nil || !res.Equal(funcs.ListValue(num("-10"), num("20"), num("30"))) {
+		t.Error("incorrect result of the script: ", res, err)
+	}
+
+	// the body of a lambda in a defined function uses the parameters of the function
+	if err := p.DefineFunction("total(ys, r) = reduce(ys, 0, (acc, y) -> acc + y * r)"); err != nil {
+		t.Fatal(err)
+	}
+	exp, _ = p.Parse("total(xs, 2)")
+	if res, err := exp.EvaluateValue(vars); err != nil || !res.Equal(num("8")) {
+		t.Error("incorrect result of the defined function: ", res, err)
+	}
+
+	type ErrorData struct {
+		input string
+		err   string
+	}
+	parseErrors := []ErrorData{
+		{"(x, x) -> 1", "duplicate parameter 'x' at line 1, column 5"},
+		{"pi -> 1", "parameter 'pi' is a constant at line 1, column 1"},
+		{"(pi, x) -> 1", "parameter 'pi' is a constant at line 1, column 2"},
+		{"(a + b) -> 1", "unexpected '->' at line 1, column 9: expected operator"},
+		{"map(xs, x ->)", "unexpected ')' at line 1, column 13: expected operand"},
+	}
+	for _, d := range parseErrors {
+		_, err := p.Parse(d.input)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+	if err := p.DefineFunction("bad(ys) = map(ys, y -> y * z)"); err == nil || err.Error() != "unknown variable 'z' in function 'bad' at line 1, column 28" {
+		t.Error("incorrect error of the definition: ", err)
+	}
+
+	evalErrors := []ErrorData{
+		{"map(xs, (a, b) -> a)", "incorrect count of args for function '( -> ( a,b ) a )'. Need: 2, but get: 1"},
+		{`filter(xs, x -> "a")`, `incorrect type of the result of '( -> ( x ) "a" )'. Need: bool, but get: string`},
+		{"map(rate, x -> x)", "incorrect type of argument 1 of 'map'. Need: list, but get: number"},
+		{"map(xs, rate)", "incorrect type of argument 2 of 'map'. Need: function, but get: number"},
+	}
+	for _, d := range evalErrors {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		_, err = exp.EvaluateValue(vars)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+	exp, _ = p.Parse("x -> x")
+	if _, err := exp.Evaluate(nil); err == nil || err.Error() != "incorrect type of the result. Need: number, but get: function" {
+		t.Error("incorrect numeric error: ", err)
+	}
+}
+
+func TestParseMembers(t *testing.T) {
+	type TestData struct {
+		input  string
+		output string
+		vars   []string
+		res    funcs.Value
+	}
+	num := func(s string) funcs.Value {
+		return funcs.NumberValue(decimal.RequireFromString(s))
+	}
+	data := []TestData{
+		{"order.total * (1 - customer.tier.discount)", "( * ( order . total ) ( - 1 ( ( customer . tier ) . discount ) ) )",
+			[]string{"customer.tier.discount", "order.total"}, num("108")},
+		{"order.lines[1].price", "( ( ( order . lines ) [ 1 ] ) . price )", []string{"order.lines"}, num("5.5")},
+		{"sum(map(order.lines, l -> l.price * l.qty))", "( sum ( ( map ( ( order . lines ),( -> ( l ) ( * ( l . price ) ( l . qty ) ) ) ) ) ) )",
+			[]string{"order.lines"}, num("25.5")},
+		{"-order.total^2", "( - ( ^ ( order . total ) 2 ) )", []string{"order.total"}, num("-14400")},
+		{"(order).note == null", "( == ( order . note ) null )", []string{"order.note"}, funcs.BoolValue(true)},
+		{"order . total", "( order . total )", []string{"order.total"}, num("120")},
+		{`customer.tier.name == "gold" ? order.total : 0`, `( if ( ( == ( ( customer . tier ) . name ) "gold" ),( order . total ),0 ) )`,
+			[]string{"customer.tier.name", "order.total"}, num("120")},
+	}
+
+	p := NewParser()
+	order, err := funcs.JSONValue([]byte(`{"total": 120, "note": null, "lines": [{"price": 10, "qty": 2}, {"price": 5.5, "qty": 1}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type tier struct {
+		Name     string  `json:"name"`
+		Discount float64 `json:"discount"`
+	}
+	vars, err := funcs.ValuesOf(map[string]interface{}{
+		"order":    order,
+		"customer": map[string]interface{}{"tier": &tier{Name: "gold", Discount: 0.1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range data {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if exp.String() != d.output {
+			t.Error("incorrect tree for '" + d.input + "', need: " + d.output + ", but get: " + exp.String())
+		}
+		if v := GetVarList(exp); !reflect.DeepEqual(v, d.vars) {
+			t.Error("incorrect variables of '"+d.input+"': ", v)
+		}
+		res, err := exp.EvaluateValue(vars)
+		if err != nil || !res.Equal(d.res) {
+			t.Error("incorrect result of '"+d.input+"': ", res, err)
+		}
+	}
+
+	// numeric variables are passed by the paths reported by GetVarList
+	exp, _ := p.Parse("order.total * (1 - customer.tier.discount)")
+	res, err := exp.Evaluate(map[string]decimal.Decimal{"order.total": decimal.NewFromInt(50), "customer.tier.discount": decimal.RequireFromString("0.2")})
+	if err != nil || !res.Equal(decimal.NewFromInt(40)) {
+		t.Error("incorrect numeric result: ", res, err)
+	}
+
+	// a local variable hides the members of the caller's variable with the same name
+	script, _ := p.ParseScript("order = customer\norder.tier.discount")
+	if v := GetVarList(script); !reflect.DeepEqual(v, []string{"customer"}) {
+		t.Error("incorrect variables of the script: ", v)
+	}
+	if res, err := script.EvaluateValue(map[string]funcs.Value{"customer": vars["customer"], "order.tier.discount": num("1")}); err != nil || !res.Equal(num("0.1")) {
+		t.Error("incorrect result of the script: ", res, err)
+	}
+
+	type ErrorData struct {
+		input string
+		err   string
+	}
+	parseErrors := []ErrorData{
+		{"order.", "unexpected end of expression at line 1, column 7: expected member name"},
+		{"order.(total)", "unexpected '(' at line 1, column 7: expected member name"},
+		{"order.null", "unexpected 'null' at line 1, column 7: expected member name"},
+	}
+	for _, d := range parseErrors {
+		_, err := p.Parse(d.input)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
+
+	evalErrors := []ErrorData{
+		{"order.tax", "member 'tax' is not found in 'order'"},
+		{"order.total.net", "incorrect type of the object of member 'net'. Need: object, but get: number"},
+		{"order.lines[0].tax", "member 'tax' is not found in '( ( order . lines ) [ 0 ] )'"},
+		{"order.total + customer.tier", "incorrect type of argument 2 of '+'. Need: number, but get: object"},
+	}
+	for _, d := range evalErrors {
+		exp, err := p.Parse(d.input)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		_, err = exp.EvaluateValue(vars)
+		if err == nil || err.Error() != d.err {
+			t.Error("incorrect error for '"+d.input+"': ", err)
+		}
+	}
 }
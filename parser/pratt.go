@@ -0,0 +1,522 @@
+package parser
+
+import (
+	"github.com/arconomy/go-math-expression-parser/funcs"
+	"github.com/arconomy/go-math-expression-parser/funcs/userfunc"
+	"github.com/arconomy/go-math-expression-parser/interfaces"
+	"github.com/arconomy/go-math-expression-parser/internal"
+	"github.com/shopspring/decimal"
+)
+
+// binding powers of the priority levels, a higher one binds tighter.
+// Operators of operators[i] have (LevelsOfPriorities-i)*10, so operators[1] is the tightest binary level
+const (
+	bpLowest  = 0
+	bpTernary = 5                               // 'c ? a : b', lower than any binary operator
+	bpIndex   = funcs.LevelsOfPriorities*10 + 5 // 'xs[i]' and 'order.total', higher than any operator
+)
+
+// parseOptions - the Parser state which is used at parsing time only
+type parseOptions struct {
+	constants   map[string]constant
+	implicitMul bool // '2x', '3(a + b)' and '(a + b)(c - d)' are multiplications
+	script      bool // statements separated by ';' or line breaks, see parseScript
+}
+
+// exprParser - precedence-climbing parser over the token stream of a single expression
+type exprParser struct {
+	ctx    interfaces.Context
+	opts   parseOptions
+	tokens []token
+	pos    int
+
+	// the state of a function definition, see parseDefinition
+	defining string              // the name of the defined function
+	params   map[string]struct{} // the only variables of the definition, nil for an expression
+	calls    []token             // the names of called functions
+
+	positions map[interfaces.Expression]token // the tokens of the nodes, see at
+}
+
+// at - remember the token of the node: the operator, the function name or the operand.
+// The tree nodes have no positions, so the type checker reports errors at these tokens
+func (ep *exprParser) at(t token, exp interfaces.Expression) interfaces.Expression {
+	if ep.positions == nil {
+		ep.positions = make(map[interfaces.Expression]token)
+	}
+	ep.positions[exp] = t
+	return exp
+}
+
+func (ep *exprParser) peek() token {
+	return ep.tokens[ep.pos]
+}
+
+func (ep *exprParser) next() token {
+	t := ep.tokens[ep.pos]
+	if t.kind != tokEOF {
+		ep.pos++
+	}
+	return t
+}
+
+func (ep *exprParser) expect(kind tokenKind, text string) error {
+	if t := ep.peek(); t.kind != kind {
+		return unexpected(t, "'"+text+"'")
+	}
+	ep.next()
+	return nil
+}
+
+// parseExpression - parse operands joined by binary operators with binding power higher than minBP
+func (ep *exprParser) parseExpression(minBP int) (interfaces.Expression, error) {
+	left, err := ep.parsePrefix()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := ep.peek()
+		if t.kind == tokQuestion && bpTernary > minBP {
+			ep.next()
+			if left, err = ep.parseTernary(t, left); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if t.kind == tokLBracket {
+			if bpIndex <= minBP {
+				return left, nil
+			}
+			ep.next()
+			index, err := ep.parseExpression(bpLowest)
+			if err != nil {
+				return nil, err
+			}
+			if err := ep.expect(tokRBracket, "]"); err != nil {
+				return nil, err
+			}
+			left = ep.at(t, &internal.Index{Exp: left, Index: index})
+			continue
+		}
+		if t.kind == tokDot {
+			if bpIndex <= minBP {
+				return left, nil
+			}
+			ep.next()
+			name := ep.next()
+			if name.kind != tokIdent {
+				return nil, unexpected(name, "member name")
+			}
+			left = ep.at(name, &internal.Member{Exp: left, Name: name.text})
+			continue
+		}
+		if startsOperand(t) {
+			if !ep.implicitMultiplication(t) {
+				return nil, &ParseError{Offset: t.pos, Found: t.text, Msg: "missing operator between operands"}
+			}
+			// '2x' is parsed as '2 * x', so it has the precedence of '*'
+			bp, ok := ep.infixBindingPower("*")
+			if !ok {
+				return nil, &ParseError{Offset: t.pos, Found: t.text, Msg: "implicit multiplication needs the '*' operator"}
+			}
+			if bp <= minBP {
+				return left, nil
+			}
+			right, err := ep.parseExpression(bp)
+			if err != nil {
+				return nil, err
+			}
+			left = ep.at(t, &internal.Node{Op: "*", LExp: left, RExp: right})
+			continue
+		}
+		if t.kind != tokOperator {
+			return left, nil
+		}
+		if op, ok := ep.postfixOperator(); ok {
+			if levelBindingPower(op.Level) <= minBP {
+				return left, nil
+			}
+			ep.next()
+			left = ep.at(t, &internal.Postfix{Op: t.text, Exp: left})
+			continue
+		}
+		bp, ok := ep.infixBindingPower(t.text)
+		if !ok {
+			return nil, unexpected(t, "binary operator")
+		}
+		if bp <= minBP {
+			return left, nil
+		}
+		ep.next()
+		if ep.ctx.Functions().Associativity(t.text) == funcs.RightAssoc {
+			// the right operand takes the next operators of the same level
+			bp--
+		}
+		right, err := ep.parseExpression(bp)
+		if err != nil {
+			return nil, err
+		}
+		left = ep.at(t, &internal.Node{Op: t.text, LExp: left, RExp: right})
+	}
+}
+
+func (ep *exprParser) parsePrefix() (interfaces.Expression, error) {
+	// an operand is expected, so the line break doesn't end the statement: 'a +\n b' is 'a + b'
+	for ep.peek().kind == tokSeparator && ep.peek().text == "\n" {
+		ep.next()
+	}
+	t := ep.next()
+	switch t.kind {
+	case tokNumber:
+		if !internal.IsNumber(t.text) {
+			return nil, &ParseError{Offset: t.pos, Found: t.text, Msg: "incorrect number '" + t.text + "'"}
+		}
+		return ep.at(t, &internal.Term{Val: t.text}), nil
+
+	case tokLiteral:
+		return ep.at(t, &internal.Term{Val: t.text}), nil
+
+	case tokIdent:
+		if ep.peek().kind == tokArrow {
+			return ep.parseLambda([]token{t})
+		}
+		if ep.peek().kind == tokLParen {
+			// 'x(a + b)' is a multiplication if x is not a function
+			if _, ok := ep.ctx.Functions().Describe(t.text); ok || !ep.opts.implicitMul {
+				return ep.parseFunc(t)
+			}
+		}
+		// a constant is folded into the value, so it is not a variable
+		if c, ok := ep.opts.constants[t.text]; ok {
+			return ep.at(t, &internal.Term{Val: c(ep.ctx.Settings()).String()}), nil
+		}
+		if _, ok := ep.params[t.text]; !ok && ep.params != nil {
+			return nil, &ParseError{Offset: t.pos, Found: t.text,
+				Msg: "unknown variable '" + t.text + "' in function '" + ep.defining + "'"}
+		}
+		return ep.at(t, &internal.Term{Val: t.text}), nil
+
+	case tokLParen:
+		if params, ok := ep.lambdaParams(); ok {
+			for ep.peek().kind != tokArrow {
+				ep.next()
+			}
+			return ep.parseLambda(params)
+		}
+		exp, err := ep.parseExpression(bpLowest)
+		if err != nil {
+			return nil, err
+		}
+		if err := ep.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		return exp, nil
+
+	case tokLBracket:
+		return ep.parseList(t)
+
+	case tokOperator:
+		op, ok := ep.ctx.Functions().Operator(funcs.Prefix, t.text)
+		if !ok {
+			return nil, unexpected(t, "unary operator")
+		}
+		// the operand contains operators with lower levels only
+		exp, err := ep.parseExpression(levelBindingPower(op.Level))
+		if err != nil {
+			return nil, err
+		}
+		return ep.at(t, &internal.Unary{Op: t.text, Exp: exp}), nil
+	}
+	return nil, unexpected(t, "operand")
+}
+
+// parseScript - parse statements separated by ';' or line breaks into internal.Script.
+// A statement is an assignment 'name = exp' or an expression
+func (ep *exprParser) parseScript() (interfaces.Expression, error) {
+	script := new(internal.Script)
+	for {
+		for ep.peek().kind == tokSeparator {
+			ep.next()
+		}
+		if ep.peek().kind == tokEOF {
+			break
+		}
+		st, err := ep.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		script.Statements = append(script.Statements, st)
+		if t := ep.peek(); t.kind != tokSeparator && t.kind != tokEOF {
+			return nil, unexpected(t, "operator", "';'", "line break")
+		}
+	}
+	if len(script.Statements) == 0 {
+		return &internal.Term{Val: decimal.Zero.String()}, nil
+	}
+	return script, nil
+}
+
+func (ep *exprParser) parseStatement() (interfaces.Expression, error) {
+	name := ep.peek()
+	if name.kind != tokIdent || ep.tokens[ep.pos+1].kind != tokAssign {
+		return ep.parseExpression(bpLowest)
+	}
+	if _, ok := ep.opts.constants[name.text]; ok {
+		return nil, &ParseError{Offset: name.pos, Found: name.text, Msg: "cannot assign to constant '" + name.text + "'"}
+	}
+	if _, ok := ep.ctx.Functions().Describe(name.text); ok {
+		return nil, &ParseError{Offset: name.pos, Found: name.text, Msg: "cannot assign to function '" + name.text + "'"}
+	}
+	ep.next()
+	ep.next() // '='
+	exp, err := ep.parseExpression(bpLowest)
+	if err != nil {
+		return nil, err
+	}
+	return ep.at(name, &internal.Assign{Name: name.text, Exp: exp}), nil
+}
+
+// parseTernary - parse 'cond ? a : b' into the call of the lazy 'if' function.
+// The operator is right-associative: 'a ? b : c ? d : e' is 'a ? b : (c ? d : e)'
+func (ep *exprParser) parseTernary(question token, cond interfaces.Expression) (interfaces.Expression, error) {
+	if _, ok := ep.ctx.Functions().Describe("if"); !ok {
+		return nil, &ParseError{Offset: question.pos, Found: question.text, Msg: "conditional operator needs the 'if' function"}
+	}
+	then, err := ep.parseExpression(bpLowest)
+	if err != nil {
+		return nil, err
+	}
+	if err := ep.expect(tokColon, ":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := ep.parseExpression(bpTernary - 1)
+	if err != nil {
+		return nil, err
+	}
+	return ep.at(question, &userfunc.Func{Op: "if", Args: []interfaces.Expression{cond, then, otherwise}}), nil
+}
+
+// parseList - parse the comma-separated items of the list literal '[a, b, c]'
+func (ep *exprParser) parseList(bracket token) (interfaces.Expression, error) {
+	list := new(internal.List)
+	if ep.peek().kind == tokRBracket {
+		ep.next()
+		return ep.at(bracket, list), nil
+	}
+	for {
+		item, err := ep.parseExpression(bpLowest)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+
+		switch t := ep.next(); t.kind {
+		case tokComma:
+		case tokRBracket:
+			return ep.at(bracket, list), nil
+		default:
+			return nil, unexpected(t, "','", "']'")
+		}
+	}
+}
+
+// lambdaParams - the parameters of the lambda '(a, b) -> exp' after '(', ok is false if it is not a lambda.
+// The tokens are not consumed
+func (ep *exprParser) lambdaParams() (params []token, ok bool) {
+	i := ep.pos
+	if ep.tokens[i].kind == tokRParen {
+		return nil, ep.tokens[i+1].kind == tokArrow
+	}
+	for ; ep.tokens[i].kind == tokIdent; i += 2 {
+		params = append(params, ep.tokens[i])
+		switch ep.tokens[i+1].kind {
+		case tokComma:
+		case tokRParen:
+			return params, ep.tokens[i+2].kind == tokArrow
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// parseLambda - parse the body of the lambda after its parameters, the next token is '->'.
+// The body takes the rest of the expression: 'x -> x + 1' is 'x -> (x + 1)'
+func (ep *exprParser) parseLambda(params []token) (interfaces.Expression, error) {
+	arrow := ep.next()
+	lambda := &internal.Lambda{}
+	for _, t := range params {
+		if _, ok := ep.opts.constants[t.text]; ok {
+			return nil, &ParseError{Offset: t.pos, Found: t.text, Msg: "parameter '" + t.text + "' is a constant"}
+		}
+		for _, name := range lambda.Params {
+			if name == t.text {
+				return nil, &ParseError{Offset: t.pos, Found: t.text, Msg: "duplicate parameter '" + t.text + "'"}
+			}
+		}
+		lambda.Params = append(lambda.Params, t.text)
+	}
+
+	// the body of a lambda in a defined function can use the parameters of both
+	if outer := ep.params; outer != nil {
+		ep.params = make(map[string]struct{}, len(outer)+len(params))
+		for name := range outer {
+			ep.params[name] = struct{}{}
+		}
+		for _, name := range lambda.Params {
+			ep.params[name] = struct{}{}
+		}
+		defer func() { ep.params = outer }()
+	}
+	body, err := ep.parseExpression(bpLowest)
+	if err != nil {
+		return nil, err
+	}
+	lambda.Body = body
+	return ep.at(arrow, lambda), nil
+}
+
+// parseFunc - parse a comma-separated list of the function arguments
+// and check their count with the function descriptor
+func (ep *exprParser) parseFunc(name token) (interfaces.Expression, error) {
+	if name.text == ep.defining {
+		return nil, &ParseError{Offset: name.pos, Found: name.text, Msg: "recursive call of function '" + name.text + "'"}
+	}
+	ep.calls = append(ep.calls, name)
+	d, ok := ep.ctx.Functions().Describe(name.text)
+	if !ok {
+		return nil, &ParseError{Offset: name.pos, Found: name.text, Msg: "function '" + name.text + "' is not supported"}
+	}
+	f := new(userfunc.Func)
+	f.SetOperation(name.text)
+	ep.next() // '('
+
+	if ep.peek().kind == tokRParen {
+		ep.next()
+	} else {
+		for done := false; !done; {
+			arg, err := ep.parseExpression(bpLowest)
+			if err != nil {
+				return nil, err
+			}
+			f.SetArgs(append(f.GetArgs(), arg))
+
+			switch t := ep.next(); t.kind {
+			case tokComma:
+			case tokRParen:
+				done = true
+			default:
+				return nil, unexpected(t, "','", "')'")
+			}
+		}
+	}
+
+	if err := d.CheckArgs(len(f.GetArgs())); err != nil {
+		return nil, &ParseError{Offset: name.pos, Found: name.text, Msg: err.Error()}
+	}
+	return ep.at(name, f), nil
+}
+
+func (ep *exprParser) infixBindingPower(op string) (int, bool) {
+	indx, ok := internal.BinaryOperatorExist(op, ep.ctx)
+	if !ok {
+		return 0, false
+	}
+	return levelBindingPower(indx), true
+}
+
+// postfixOperator - return the postfix operator of the current token. An operator which is binary too
+// is postfix only if no number, name or '(' follows it: '15% - 1' is postfix and '15 % 4' is binary
+func (ep *exprParser) postfixOperator() (funcs.Operator, bool) {
+	t := ep.peek()
+	op, ok := ep.ctx.Functions().Operator(funcs.Postfix, t.text)
+	if !ok {
+		return op, false
+	}
+	if _, binary := ep.ctx.Functions().Operator(funcs.Infix, t.text); binary && startsOperand(ep.tokens[ep.pos+1]) {
+		return op, false
+	}
+	return op, true
+}
+
+func levelBindingPower(level int) int {
+	return (funcs.LevelsOfPriorities - level) * 10
+}
+
+// implicitMultiplication - checks that the operand starting with the token t and the previous operand
+// are multiplied: a number followed by a name or '(', a name followed by '(',
+// and ')' followed by a name or '('. Other adjacent operands, like '2 3' or 'a b', are errors
+func (ep *exprParser) implicitMultiplication(t token) bool {
+	if !ep.opts.implicitMul || ep.pos == 0 {
+		return false
+	}
+	switch ep.tokens[ep.pos-1].kind {
+	case tokNumber, tokRParen:
+		return t.kind == tokIdent || t.kind == tokLParen
+	case tokIdent:
+		return t.kind == tokLParen
+	}
+	return false
+}
+
+// startsOperand - checks that the token can only be the beginning of an operand, like a number or '('
+func startsOperand(t token) bool {
+	return t.kind == tokNumber || t.kind == tokIdent || t.kind == tokLParen || t.kind == tokLiteral || t.kind == tokLBracket
+}
+
+func unexpected(t token, expected ...string) error {
+	return &ParseError{Offset: t.pos, Found: t.text, Expected: expected}
+}
+
+// parseStr - tokenize and parse a single expression with the functions of the context
+func parseStr(str string, ctx interfaces.Context) (interfaces.Expression, error) {
+	_, exp, err := parseStrWith(str, ctx, parseOptions{})
+	return exp, err
+}
+
+// parseStrWith - tokenize and parse a single expression with the functions of the context and the options,
+// the returned exprParser keeps the positions of the nodes
+func parseStrWith(str string, ctx interfaces.Context, opts parseOptions) (*exprParser, interfaces.Expression, error) {
+	l := newLexer(str, operatorSymbols(ctx))
+	l.script = opts.script
+	tokens, err := l.tokenize()
+	if err != nil {
+		return nil, nil, err
+	}
+	ep := &exprParser{ctx: ctx, opts: opts, tokens: tokens}
+	if opts.script {
+		exp, err := ep.parseScript()
+		return ep, exp, err
+	}
+	if tokens[0].kind == tokEOF {
+		return ep, &internal.Term{Val: decimal.Zero.String()}, nil
+	}
+	exp, err := ep.parseExpression(bpLowest)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t := ep.peek(); t.kind != tokEOF {
+		return nil, nil, unexpected(t, "operator")
+	}
+	return ep, exp, nil
+}
+
+// operatorSymbols - return all registered names which are not identifiers, like '+' or '^'
+func operatorSymbols(ctx interfaces.Context) []string {
+	set := make(map[string]struct{})
+	for level := 0; level < funcs.LevelsOfPriorities; level++ {
+		for _, s := range ctx.Functions().Names(level) {
+			if !isIdentifier(s) {
+				set[s] = struct{}{}
+			}
+		}
+	}
+	for _, s := range ctx.Functions().PostfixNames() {
+		set[s] = struct{}{}
+	}
+	ops := make([]string, 0, len(set))
+	for s := range set {
+		ops = append(ops, s)
+	}
+	return ops
+}